@@ -0,0 +1,85 @@
+package addlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// 级联选择器(cascader)的导出选项. 零值表示默认选项.
+type CascaderOptions struct {
+	ValueField    string    // 编码的字段名, 默认为"value"
+	LabelField    string    // 名称的字段名, 默认为"label"
+	ChildrenField string    // 孩子的字段名, 默认为"children". 叶子节点没有该字段
+	Set           RegionSet // 只导出区域集合中的地址, 默认为SetAll
+	MaxDepth      int       // 最大层数, 例如2表示只导出省和市. 若<=0, 则不限制
+	Indent        string    // 缩进, 例如"  ". 若为"", 则输出紧凑的JSON
+}
+
+// 把地址树导出为级联选择器使用的嵌套JSON.
+// 例: [{"value":"CN033000000","label":"浙江省","children":[{"value":"CN033001000","label":"杭州市",...}]}]
+func ExportCascader(w io.Writer, opts CascaderOptions) error {
+	if opts.ValueField == "" {
+		opts.ValueField = "value"
+	}
+	if opts.LabelField == "" {
+		opts.LabelField = "label"
+	}
+	if opts.ChildrenField == "" {
+		opts.ChildrenField = "children"
+	}
+	if opts.Set == "" {
+		opts.Set = SetAll
+	}
+	data, err := json.Marshal(cascaderNodes{&opts, ROOT, 1})
+	if err != nil {
+		return err
+	}
+	if opts.Indent != "" {
+		var buf bytes.Buffer
+		json.Indent(&buf, data, "", opts.Indent)
+		data = buf.Bytes()
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// 节点parent的孩子列表, depth为孩子的层数(省为1).
+// 按照选项中的字段名输出, 且字段的顺序固定为: 编码, 名称, 孩子.
+type cascaderNodes struct {
+	opts   *CascaderOptions
+	parent string
+	depth  int
+}
+
+func (n cascaderNodes) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, code := range filterByRegionSet(libItems[n.parent].children, n.opts.Set) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fields := [][2]any{{n.opts.ValueField, code}, {n.opts.LabelField, libItems[code].name}}
+		hasChildren := len(filterByRegionSet(libItems[code].children, n.opts.Set)) > 0
+		if hasChildren && (n.opts.MaxDepth <= 0 || n.depth < n.opts.MaxDepth) {
+			fields = append(fields, [2]any{n.opts.ChildrenField, cascaderNodes{n.opts, code, n.depth + 1}})
+		}
+		buf.WriteByte('{')
+		for j, field := range fields {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(field[0])
+			value, err := json.Marshal(field[1])
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
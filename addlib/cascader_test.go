@@ -0,0 +1,59 @@
+package addlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExportCascader(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportCascader(&buf, CascaderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var tree []struct {
+		Value    string
+		Label    string
+		Children []struct {
+			Value    string
+			Label    string
+			Children []struct{ Value, Label string }
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &tree); err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != len(ProvinceCodes(false)) {
+		t.Errorf("expected: %d, got: %d", len(ProvinceCodes(false)), len(tree))
+	}
+	nodes := 0
+	for _, p := range tree {
+		nodes++
+		for _, c := range p.Children {
+			nodes += 1 + len(c.Children)
+		}
+	}
+	if expected := len(Descendants(ROOT, 0)); nodes != expected {
+		t.Errorf("expected: %d nodes, got: %d", expected, nodes)
+	}
+	if !strings.HasPrefix(buf.String(), `[{"value":"`) {
+		t.Errorf("expected field order value, label, children, got: %.40s", buf.String())
+	}
+
+	tests := []struct {
+		opts     CascaderOptions
+		expected string
+	}{
+		{CascaderOptions{ValueField: "code", LabelField: "name", MaxDepth: 1, Set: SetHMT},
+			`[{"code":"CN002000000","name":"澳门特别行政区"},{"code":"CN027000000","name":"台湾省"},{"code":"CN029000000","name":"香港特别行政区"}]`},
+		{CascaderOptions{Set: "foo"}, "[]"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		ExportCascader(&buf, tt.opts)
+		if got := strings.TrimSpace(buf.String()); got != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got)
+		}
+	}
+}
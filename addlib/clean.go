@@ -0,0 +1,223 @@
+package addlib
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// 批量清洗CSV的选项
+type CleanOptions struct {
+	ProvinceColumn string // 省名所在的列(表头中的名称)
+	CityColumn     string // 市名所在的列
+	DistrictColumn string // 区名所在的列
+	TextColumn     string // 地址文本所在的列. 省市区名称都为空时按自由文本解析(参考Resolve)
+	Comma          rune   // 分隔符, 默认为','
+	Workers        int    // 并发数, 默认为GOMAXPROCS
+}
+
+// 批量清洗的统计
+type CleanStats struct {
+	Rows     int // 输入的行数(不含表头)
+	Resolved int // 写入out的行数
+	Rejected int // 写入reject的行数
+}
+
+// 清洗结果追加的列
+var (
+	cleanColumns  = []string{"norm_province", "norm_city", "norm_district", "norm_code", "norm_detail"}
+	rejectColumns = []string{"reject_status", "reject_reason"}
+)
+
+// 批量清洗CSV中的地址.
+// 第1行为表头, 用opts指定地址所在的列. 每行按Resolve的规则解析, 多个worker并发处理, 输出的顺序与输入相同.
+// 1. 解析成功的行写入out: 原来的列, 以及norm_province, norm_city, norm_district, norm_code, norm_detail.
+// 2. 解析失败的行写入reject: 原来的列, 以及reject_status(错误码, 参考ErrorCodeOf: bad_input, not_found, ambiguous或inconsistent)和reject_reason. 若reject为nil, 则丢弃.
+// 列数与表头不同的行按ResolveRow的规则对齐: 多出的列不为空时该行写入reject(bad_input), 不截断数据.
+// 同时处理中的行数有上限, 因此内存占用与输入的大小无关.
+// 若CSV格式错误或写入失败, 则停止并返回错误, 此时已经写入的行保持不变.
+// 注意: 清洗期间不能调用Init等修改地址库的函数.
+func CleanCSV(r io.Reader, out io.Writer, reject io.Writer, opts CleanOptions) (CleanStats, error) {
+	var stats CleanStats
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	header, err := reader.Read()
+	if err != nil {
+		return stats, fmt.Errorf("cannot read header: %w", err)
+	}
+	var indexes [4]int
+	for k, column := range []string{opts.ProvinceColumn, opts.CityColumn, opts.DistrictColumn, opts.TextColumn} {
+		i := -1
+		for j, h := range header {
+			if column != "" && h == column {
+				i = j
+				break
+			}
+		}
+		if column != "" && i < 0 {
+			return stats, fmt.Errorf("column not found, column = %s", column)
+		}
+		indexes[k] = i
+	}
+	if indexes[0] < 0 && indexes[1] < 0 && indexes[2] < 0 && indexes[3] < 0 {
+		return stats, errors.New("no address column")
+	}
+
+	if reject == nil {
+		reject = io.Discard
+	}
+	outWriter, rejectWriter := csv.NewWriter(out), csv.NewWriter(reject)
+	outWriter.Comma, rejectWriter.Comma = reader.Comma, reader.Comma
+	outWriter.Write(append(append([]string{}, header...), cleanColumns...))
+	rejectWriter.Write(append(append([]string{}, header...), rejectColumns...))
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	type job struct {
+		seq int
+		row []string
+	}
+	type result struct {
+		job
+		resolution Resolution
+		err        error
+	}
+	jobs := make(chan job, workers)
+	results := make(chan result, workers)
+	// 已读取但尚未写出的行数上限. 写出一行后才能再读取一行.
+	tokens := make(chan struct{}, workers*64)
+	stop := make(chan struct{})
+
+	// 读取
+	var readErr error
+	go func() {
+		defer close(jobs)
+		for seq := 0; ; seq++ {
+			// 写入失败后不再读取
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			row, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				readErr = err
+				return
+			}
+			select {
+			case tokens <- struct{}{}:
+			case <-stop:
+				return
+			}
+			jobs <- job{seq, row}
+		}
+	}()
+
+	// 解析
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				res := result{job: j}
+				res.row, res.resolution, res.err = ResolveRow(j.row, len(header), indexes)
+				results <- res
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 按输入的顺序写出
+	var writeErr error
+	pending := make(map[int]result)
+	next := 0
+	for res := range results {
+		pending[res.seq] = res
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-tokens
+			if writeErr != nil {
+				continue
+			}
+			stats.Rows++
+			if res.err != nil {
+				stats.Rejected++
+				writeErr = rejectWriter.Write(append(res.row, string(ErrorCodeOf(res.err)), res.err.Error()))
+			} else {
+				stats.Resolved++
+				add := res.resolution
+				writeErr = outWriter.Write(append(res.row, add.Province, add.City, add.District, add.Code, add.Detail))
+			}
+			if writeErr != nil {
+				close(stop)
+			}
+		}
+	}
+
+	outWriter.Flush()
+	rejectWriter.Flush()
+	if writeErr == nil {
+		writeErr = outWriter.Error()
+	}
+	if writeErr == nil {
+		writeErr = rejectWriter.Error()
+	}
+	if writeErr != nil {
+		return stats, writeErr
+	}
+	return stats, readErr
+}
+
+// 解析表格中的一行地址, 供CleanCSV和addlib normalize共用.
+// 输入一行, 表头的列数n, 以及省, 市, 区, 地址文本所在的列(-1表示没有该列), 输出对齐到n列的行和解析结果(参考Resolve).
+// 列数不足n的行补齐空字符串. 多出的列都为空时去掉, 否则返回ErrBadInput, 输出的行为前n列, 多出的列记录在错误信息中.
+// 错误: ErrBadInput - 多出的列不为空, 或地址列都为空; 其它参考Resolve.
+func ResolveRow(row []string, n int, columns [4]int) ([]string, Resolution, error) {
+	if len(row) > n {
+		extra := row[n:]
+		row = row[:n]
+		for _, value := range extra {
+			if value != "" {
+				err := fmt.Errorf("%w: too many columns, header = %d, extra = %s", ErrBadInput, n, strings.Join(extra, ","))
+				return row, Resolution{}, err
+			}
+		}
+	} else if len(row) < n {
+		row = append(row, make([]string, n-len(row))...)
+	}
+	var values [4]string
+	for i, column := range columns {
+		if column >= 0 && column < len(row) {
+			values[i] = row[column]
+		}
+	}
+	if values == ([4]string{}) {
+		return row, Resolution{}, errEmptyAddress
+	}
+	res, err := Resolve(values[0], values[1], values[2], values[3])
+	return row, res, err
+}
+
+// 地址列都为空
+var errEmptyAddress = fmt.Errorf("%w: empty address", ErrBadInput)
@@ -0,0 +1,146 @@
+package addlib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCleanCSV(t *testing.T) {
+	input := "id,省,市,区,地址\n" +
+		"1,浙江,杭州,西湖,\n" +
+		"2,,,,上海浦东新区世纪大道\n" +
+		"3,江苏,杭州,,\n" +
+		"4,,,,\n" +
+		"5,,张家,,\n" +
+		"6,,,,西湖区文三路\n"
+	var out, reject bytes.Buffer
+	opts := CleanOptions{ProvinceColumn: "省", CityColumn: "市", DistrictColumn: "区", TextColumn: "地址", Workers: 3}
+	stats, err := CleanCSV(strings.NewReader(input), &out, &reject, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (CleanStats{6, 2, 4}) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	expected := "id,省,市,区,地址,norm_province,norm_city,norm_district,norm_code,norm_detail\n" +
+		"1,浙江,杭州,西湖,,浙江省,杭州市,西湖区,CN033001012,\n" +
+		"2,,,,上海浦东新区世纪大道,上海市,上海市,浦东新区,CN023001010,世纪大道\n"
+	if out.String() != expected {
+		t.Errorf("expected: %s, got: %s", expected, out.String())
+	}
+	lines := strings.Split(strings.TrimSpace(reject.String()), "\n")
+	statuses := []string{"reject_status", "inconsistent", "bad_input", "ambiguous", "ambiguous"}
+	for i, line := range lines {
+		if !strings.Contains(line, ","+statuses[i]+",") {
+			t.Errorf("expected status %s, got: %s", statuses[i], line)
+		}
+	}
+}
+
+func TestCleanCSVRagged(t *testing.T) {
+	input := "id,市,区,备注\n" +
+		"1,杭州,西湖\n" +
+		"2,杭州,西湖,a,b\n" +
+		"3,foo\n" +
+		"4,杭州,西湖,a,,\n"
+	var out, reject bytes.Buffer
+	opts := CleanOptions{CityColumn: "市", DistrictColumn: "区"}
+	if _, err := CleanCSV(strings.NewReader(input), &out, &reject, opts); err != nil {
+		t.Fatal(err)
+	}
+	expected := "id,市,区,备注,norm_province,norm_city,norm_district,norm_code,norm_detail\n" +
+		"1,杭州,西湖,,浙江省,杭州市,西湖区,CN033001012,\n" +
+		"4,杭州,西湖,a,浙江省,杭州市,西湖区,CN033001012,\n"
+	if out.String() != expected {
+		t.Errorf("expected: %s, got: %s", expected, out.String())
+	}
+	expected = "id,市,区,备注,reject_status,reject_reason\n" +
+		"2,杭州,西湖,a,bad_input,\"bad input: too many columns, header = 4, extra = b\"\n" +
+		"3,foo,,,not_found,\"not found, level = city, input = foo\"\n"
+	if reject.String() != expected {
+		t.Errorf("expected: %s, got: %s", expected, reject.String())
+	}
+}
+
+func TestCleanCSVOrder(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("id\taddress\n")
+	cities := []string{"杭州", "南京", "foo", "成都", "西安"}
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&input, "%d\t%s\n", i, cities[i%len(cities)])
+	}
+	var out, reject bytes.Buffer
+	opts := CleanOptions{TextColumn: "address", Comma: '\t', Workers: 8}
+	stats, err := CleanCSV(strings.NewReader(input.String()), &out, &reject, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (CleanStats{5000, 4000, 1000}) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n")[1:] {
+		if expected := fmt.Sprintf("%d\t", i/4*5+[]int{0, 1, 3, 4}[i%4]); !strings.HasPrefix(line, expected) {
+			t.Fatalf("expected: %s..., got: %s", expected, line)
+		}
+	}
+}
+
+func TestCleanCSVErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  CleanOptions
+		rows  int
+	}{
+		{"", CleanOptions{TextColumn: "address"}, 0},
+		{"id,address\n", CleanOptions{TextColumn: "foo"}, 0},
+		{"id,address\n", CleanOptions{}, 0},
+		{"id,address\n1,杭州\n2,\"杭州\n", CleanOptions{TextColumn: "address", Workers: 2}, 1},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		stats, err := CleanCSV(strings.NewReader(tt.input), &out, nil, tt.opts)
+		if err == nil || stats.Rows != tt.rows {
+			t.Errorf("expected error after %d rows, got: %+v, %v", tt.rows, stats, err)
+		}
+	}
+}
+
+// 写入失败的Writer
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// 记录读取的字节数的Reader
+type countReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestCleanCSVWriteError(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("id,address\n")
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&input, "%d,杭州\n", i)
+	}
+	r := &countReader{r: strings.NewReader(input.String())}
+	_, err := CleanCSV(r, failWriter{}, nil, CleanOptions{TextColumn: "address", Workers: 2})
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("expected: disk full, got: %v", err)
+	}
+	// 写入失败后停止读取
+	if r.n >= input.Len() {
+		t.Errorf("expected to stop reading, read %d of %d bytes", r.n, input.Len())
+	}
+}
@@ -0,0 +1,112 @@
+package addlib
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 错误类型. 用errors.Is判断, 例如: errors.Is(err, ErrNotFound)
+var (
+	ErrNotFound     = errors.New("not found")            // 地址名称或编码不存在
+	ErrAmbiguous    = errors.New("ambiguous name")       // 地址名称对应多个编码, 需要输入更多的汉字
+	ErrInconsistent = errors.New("inconsistent address") // 省市区之间不存在管辖关系, 或数据引用了不存在的编码
+	ErrDataFormat   = errors.New("wrong data format")    // 数据文件格式错误
+	ErrBadInput     = errors.New("bad input")            // 输入无效, 例如参数为空或表格的行无效(参考ResolveRow). 查询函数不返回它
+)
+
+// 错误码, 用于跨语言的接口(动态链接库, WebAssembly, HTTP和gRPC服务)
+type ErrorCode string
+
+const (
+	CodeOK           ErrorCode = ""             // 没有错误
+	CodeBadInput     ErrorCode = "bad_input"    // ErrBadInput
+	CodeNotFound     ErrorCode = "not_found"    // ErrNotFound
+	CodeAmbiguous    ErrorCode = "ambiguous"    // ErrAmbiguous
+	CodeInconsistent ErrorCode = "inconsistent" // ErrInconsistent(查询错误)
+	CodeDataError    ErrorCode = "data_error"   // DataError或ErrDataFormat
+	CodeInternal     ErrorCode = "internal"     // 其它错误
+)
+
+// 输入错误, 输出错误码. 若err为nil, 则返回CodeOK.
+// 数据错误先于查询错误判断, 例如数据引用了不存在的编码(DataError包装的ErrInconsistent)为CodeDataError.
+func ErrorCodeOf(err error) ErrorCode {
+	var dataErr *DataError
+	switch {
+	case err == nil:
+		return CodeOK
+	case errors.As(err, &dataErr), errors.Is(err, ErrDataFormat):
+		return CodeDataError
+	case errors.Is(err, ErrBadInput):
+		return CodeBadInput
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrAmbiguous):
+		return CodeAmbiguous
+	case errors.Is(err, ErrInconsistent):
+		return CodeInconsistent
+	}
+	return CodeInternal
+}
+
+// 查询错误.
+// 用errors.As获取出错的级别和输入, 用errors.Is判断错误类型.
+type LookupError struct {
+	Level Level  // 出错的级别. 若与级别无关(例如输入编码), 则为""
+	Input string // 出错的输入
+	Err   error  // ErrNotFound, ErrAmbiguous或ErrInconsistent
+}
+
+func (e *LookupError) Error() string {
+	if e.Level == "" {
+		return fmt.Sprintf("%s, input = %s", e.Err, e.Input)
+	}
+	return fmt.Sprintf("%s, level = %s, input = %s", e.Err, e.Level, e.Input)
+}
+
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
+
+// 数据错误.
+// 用errors.As获取出错的文件和行号, 用errors.Is判断错误类型.
+type DataError struct {
+	Path   string // 数据文件的路径
+	Line   int    // 出错的行号(从1开始). 若与行无关, 则为0
+	Detail string // 错误详情
+	Err    error  // ErrDataFormat, ErrInconsistent或文件系统错误(例如fs.ErrNotExist)
+}
+
+func (e *DataError) Error() string {
+	msg := fmt.Sprintf("%s, file = %s", e.Err, e.Path)
+	if e.Line > 0 {
+		msg += fmt.Sprintf(", line = %d", e.Line)
+	}
+	if e.Detail != "" {
+		msg += ", " + e.Detail
+	}
+	return msg
+}
+
+func (e *DataError) Unwrap() error {
+	return e.Err
+}
+
+// 构造查询错误
+func lookupError(level Level, input string, err error) error {
+	return &LookupError{level, input, err}
+}
+
+// 构造数据错误. 若err已经是DataError, 则补充文件和行号.
+func dataError(filePath string, line int, err error) error {
+	var dataErr *DataError
+	if errors.As(err, &dataErr) {
+		if dataErr.Path == "" {
+			dataErr.Path = filePath
+		}
+		if dataErr.Line == 0 {
+			dataErr.Line = line
+		}
+		return dataErr
+	}
+	return &DataError{filePath, line, err.Error(), ErrDataFormat}
+}
@@ -0,0 +1,112 @@
+package addlib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestLookupCode(t *testing.T) {
+	tests := []struct {
+		inProvince string
+		inCity     string
+		inDistrict string
+		expected   string
+		err        error
+		level      Level
+	}{
+		{"浙江", "杭州", "西湖", "CN033001012", nil, ""},
+		{"", "杭州", "", "CN033001000", nil, ""},
+		{"重庆", "", "渝中", "CN034002023", nil, ""},
+		{"foo", "杭州", "", "", ErrNotFound, LevelProvince},
+		{"", "杭州", "foo", "", ErrNotFound, LevelDistrict},
+		{"浙江", "", "西湖", "", ErrNotFound, LevelCity},
+		{"江苏", "杭州", "", "", ErrInconsistent, LevelCity},
+		{"", "张家", "", "", ErrAmbiguous, LevelCity},
+		{"", "巴彦淖尔", "乌拉特", "", ErrAmbiguous, LevelDistrict},
+		{"", "", "", "", ErrNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := LookupCode(tt.inProvince, tt.inCity, tt.inDistrict)
+		if got != tt.expected || !errors.Is(err, tt.err) {
+			t.Errorf("expected: %s, %v, got: %s, %v", tt.expected, tt.err, got, err)
+		}
+		var lookupErr *LookupError
+		if tt.err != nil && (!errors.As(err, &lookupErr) || lookupErr.Level != tt.level) {
+			t.Errorf("expected LookupError with level %s, got: %v", tt.level, err)
+		}
+	}
+}
+
+func TestLookupName(t *testing.T) {
+	if got, err := LookupName("CN033001012"); got != "西湖区" || err != nil {
+		t.Errorf("expected: 西湖区, got: %s, %v", got, err)
+	}
+	if _, err := LookupName("foo"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected: %v, got: %v", ErrNotFound, err)
+	}
+	if _, err := LookupName(ROOT); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected: %v, got: %v", ErrNotFound, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := ParseAddress("foo", "bar", "")
+	var lookupErr *LookupError
+	if !errors.As(err, &lookupErr) || lookupErr.Level != LevelCity || lookupErr.Input != "bar" {
+		t.Errorf("expected LookupError for city bar, got: %v", err)
+	}
+	if _, err := ParseCode("foo"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected: %v, got: %v", ErrNotFound, err)
+	}
+}
+
+func TestInitErrors(t *testing.T) {
+	defer Init("lib.add")
+
+	dataPath := t.TempDir()
+	err := Init(dataPath)
+	var dataErr *DataError
+	if !errors.Is(err, fs.ErrNotExist) || !errors.As(err, &dataErr) ||
+		dataErr.Path != path.Join(dataPath, dataProvince) {
+		t.Errorf("expected missing %s, got: %v", dataProvince, err)
+	}
+
+	provinces := "CN001000000\t安徽省\nCN002000000\tfoo\n"
+	os.WriteFile(path.Join(dataPath, dataProvince), []byte(provinces), 0644)
+	os.WriteFile(path.Join(dataPath, dataCity), []byte{}, 0644)
+	os.WriteFile(path.Join(dataPath, dataDistrict), []byte{}, 0644)
+	err = Init(dataPath)
+	if !errors.Is(err, ErrDataFormat) || !errors.As(err, &dataErr) || dataErr.Line != 2 {
+		t.Errorf("expected data format error at line 2, got: %v", err)
+	}
+}
+
+func TestErrorCodeOf(t *testing.T) {
+	_, notFound := LookupCode("", "foo", "")
+	_, ambiguous := LookupCode("", "张家", "")
+	_, inconsistent := LookupCode("江苏", "杭州", "")
+	tests := []struct {
+		in       error
+		expected ErrorCode
+	}{
+		{nil, CodeOK},
+		{fmt.Errorf("%w: empty input", ErrBadInput), CodeBadInput},
+		{notFound, CodeNotFound},
+		{ambiguous, CodeAmbiguous},
+		{inconsistent, CodeInconsistent},
+		{&DataError{"cities.data", 3, "", ErrInconsistent}, CodeDataError},
+		{&DataError{"cities.data", 0, "", fs.ErrNotExist}, CodeDataError},
+		{errors.New("foo"), CodeInternal},
+	}
+
+	for _, tt := range tests {
+		if got := ErrorCodeOf(tt.in); got != tt.expected {
+			t.Errorf("input: %v, expected: %s, got: %s", tt.in, tt.expected, got)
+		}
+	}
+}
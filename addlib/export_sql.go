@@ -0,0 +1,184 @@
+package addlib
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SQL方言
+type Dialect string
+
+const (
+	DialectMySQL    Dialect = "mysql"
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+// SQL的导出选项. 零值表示默认选项.
+type SQLOptions struct {
+	Dialect   Dialect   // SQL方言, 默认为DialectMySQL
+	Table     string    // 表名, 默认为"divisions"
+	DropTable bool      // 是否在建表之前删除已存在的表
+	BatchSize int       // 每条INSERT语句的行数, 默认为500
+	Set       RegionSet // 只导出区域集合中的地址, 默认为SetAll
+	MaxDepth  int       // 最大层数, 例如2表示只导出省和市. 若<=0, 则不限制
+}
+
+// 导出的列. 每行对应一个地址区划(参考Region), 省的parent_code为NULL.
+var sqlColumns = []struct {
+	name     string
+	typ      string
+	nullable bool
+	value    func(r Region) string
+}{
+	{"code", "VARCHAR(16)", false, func(r Region) string { return r.Code }},
+	{"parent_code", "VARCHAR(16)", true, func(r Region) string { return r.ParentCode }},
+	{"name", "VARCHAR(64)", false, func(r Region) string { return r.Name }},
+	{"short_name", "VARCHAR(64)", false, func(r Region) string { return r.ShortName }},
+	{"level", "VARCHAR(16)", false, func(r Region) string { return string(r.Level) }},
+	{"kind", "VARCHAR(16)", false, func(r Region) string { return r.Kind.String() }},
+	{"gb_code", "VARCHAR(16)", true, func(r Region) string { return r.GBCode }},
+}
+
+// 把地址库导出为SQL脚本: 建表语句(DDL), 索引和INSERT语句.
+// 行按深度优先的顺序排列(参考Walk), 父节点先于子节点.
+// 例: addlib export -format postgres -o divisions.sql
+func ExportSQL(w io.Writer, opts SQLOptions) error {
+	ddl, inserts, err := sqlStatements(opts)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range append(ddl, inserts...) {
+		if _, err := io.WriteString(w, stmt+";\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 在数据库中建表并写入地址库. 先执行建表语句, 再在同一个事务中执行所有INSERT语句.
+// db可以是任意database/sql驱动打开的数据库. 例如用SQLite驱动打开一个文件, 即可生成SQLite数据库文件.
+// 注意: opts.Dialect需要与驱动一致. 建表语句不在事务中(MySQL的DDL会隐式提交), 因此写入失败时表可能已经存在, 可以用DropTable重新导出.
+func ExportDB(ctx context.Context, db *sql.DB, opts SQLOptions) error {
+	ddl, inserts, err := sqlStatements(opts)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range ddl {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range inserts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// 生成SQL语句(不含末尾的分号), 分别输出建表语句(DDL)和INSERT语句
+func sqlStatements(opts SQLOptions) ([]string, []string, error) {
+	if opts.Dialect == "" {
+		opts.Dialect = DialectMySQL
+	}
+	if opts.Table == "" {
+		opts.Table = "divisions"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.Set == "" {
+		opts.Set = SetAll
+	}
+	switch opts.Dialect {
+	case DialectMySQL, DialectPostgres, DialectSQLite:
+	default:
+		return nil, nil, fmt.Errorf("unknown SQL dialect, dialect = %s", opts.Dialect)
+	}
+	table := quoteIdent(opts.Dialect, opts.Table)
+
+	statements := make([]string, 0)
+	if opts.DropTable {
+		statements = append(statements, "DROP TABLE IF EXISTS "+table)
+	}
+	columns := make([]string, 0, len(sqlColumns))
+	names := make([]string, 0, len(sqlColumns))
+	for _, c := range sqlColumns {
+		typ := c.typ
+		if opts.Dialect == DialectSQLite {
+			typ = "TEXT"
+		}
+		column := "  " + quoteIdent(opts.Dialect, c.name) + " " + typ
+		if !c.nullable {
+			column += " NOT NULL"
+		}
+		columns = append(columns, column)
+		names = append(names, quoteIdent(opts.Dialect, c.name))
+	}
+	columns = append(columns, "  PRIMARY KEY ("+names[0]+")")
+	create := "CREATE TABLE " + table + " (\n" + strings.Join(columns, ",\n") + "\n)"
+	if opts.Dialect == DialectMySQL {
+		create += " DEFAULT CHARSET=utf8mb4"
+	}
+	statements = append(statements, create)
+	index := quoteIdent(opts.Dialect, "idx_"+opts.Table+"_parent_code")
+	statements = append(statements, "CREATE INDEX "+index+" ON "+table+" ("+names[1]+")")
+
+	// 区域集合包括成员的祖先, 因此导出的行的父节点也总是被导出.
+	inserts := make([]string, 0)
+	rows := make([]string, 0)
+	flush := func() {
+		if len(rows) > 0 {
+			insert := "INSERT INTO " + table + " (" + strings.Join(names, ", ") + ") VALUES\n"
+			inserts = append(inserts, insert+strings.Join(rows, ",\n"))
+			rows = rows[:0]
+		}
+	}
+	for code, depth := range Traverse(ROOT) {
+		if (opts.MaxDepth > 0 && depth > opts.MaxDepth) || !InRegionSet(code, opts.Set) {
+			continue
+		}
+		region, _ := GetRegion(code)
+		values := make([]string, 0, len(sqlColumns))
+		for _, c := range sqlColumns {
+			value := c.value(region)
+			if value == "" && c.nullable {
+				values = append(values, "NULL")
+			} else {
+				values = append(values, quoteString(opts.Dialect, value))
+			}
+		}
+		rows = append(rows, "("+strings.Join(values, ", ")+")")
+		if len(rows) >= opts.BatchSize {
+			flush()
+		}
+	}
+	flush()
+	return statements, inserts, nil
+}
+
+// 引用标识符, 例如表名和列名
+func quoteIdent(dialect Dialect, name string) string {
+	if dialect == DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// 引用字符串. MySQL默认把反斜杠当作转义字符, 因此需要额外转义.
+func quoteString(dialect Dialect, value string) string {
+	value = strings.ReplaceAll(value, "'", "''")
+	if dialect == DialectMySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + value + "'"
+}
@@ -0,0 +1,70 @@
+package addlib
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportSQL(t *testing.T) {
+	tests := []struct {
+		opts     SQLOptions
+		expected []string
+	}{
+		{SQLOptions{}, []string{"CREATE TABLE `divisions` (", "DEFAULT CHARSET=utf8mb4;",
+			"('CN033000000', NULL, '浙江省', '浙江', 'province', '省', NULL)",
+			"('CN033001012', 'CN033001000', '西湖区', '西湖', 'district', '市辖区', NULL)"}},
+		{SQLOptions{Dialect: DialectPostgres, Table: "region", DropTable: true}, []string{
+			`DROP TABLE IF EXISTS "region";`, `CREATE INDEX "idx_region_parent_code" ON "region" ("parent_code");`}},
+		{SQLOptions{Dialect: DialectSQLite}, []string{`"code" TEXT NOT NULL,`, `"gb_code" TEXT`}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := ExportSQL(&buf, tt.opts); err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range tt.expected {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("expected: %s, got: %.200s", s, buf.String())
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ExportSQL(&buf, SQLOptions{Dialect: "oracle"}); err == nil {
+		t.Errorf("expected error for unknown dialect")
+	}
+
+	// 区域集合和最大层数
+	buf.Reset()
+	if err := ExportSQL(&buf, SQLOptions{Set: SetHMT, MaxDepth: 2}); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if !strings.Contains(got, "'CN002001000'") || strings.Contains(got, "'CN033000000'") || strings.Contains(got, "'CN027001019'") {
+		t.Errorf("expected only provinces and cities in %s, got: %.200s", SetHMT, got)
+	}
+}
+
+func TestExportDB(t *testing.T) {
+	db := &fakeDB{}
+	err := ExportDB(context.Background(), openFakeDB(db), SQLOptions{Dialect: DialectSQLite, BatchSize: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !db.committed {
+		t.Errorf("expected transaction committed")
+	}
+	// 建表语句不在事务中
+	if db.begun != 2 {
+		t.Errorf("expected: 2 statements before the transaction, got: %d", db.begun)
+	}
+	rows := 0
+	for _, stmt := range db.execs[2:] {
+		rows += strings.Count(stmt, "\n(")
+	}
+	if expected := len(Descendants(ROOT, 0)); rows != expected || len(db.execs) != 2+(expected+999)/1000 {
+		t.Errorf("expected: %d rows, got: %d rows in %d statements", expected, rows, len(db.execs))
+	}
+}
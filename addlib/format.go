@@ -0,0 +1,211 @@
+package addlib
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// 名称的样式
+type NameStyle int
+
+const (
+	NameFull            NameStyle = iota // 标准名称, 例如: 浙江省
+	NameShort                            // 简称, 例如: 浙江(参考Region.ShortName)
+	NameProvinceEnglish                  // 省级名称为英文, 例如: Zhejiang. 市和区为标准名称
+	NameProvincePinyin                   // 省级名称为拼音, 例如: Zhejiang Sheng. 市和区为标准名称
+)
+
+// 地址的输出样式(参考Format)
+type Style struct {
+	Names                NameStyle          // 名称的样式
+	Separator            string             // 各级名称(以及详细地址)之间的分隔符
+	Reverse              bool               // 从下级到上级输出, 例如英文地址
+	KeepMunicipalityCity bool               // 直辖市保留市名. 默认去掉与省名相同的市名, 例如: 北京市东城区
+	Template             *template.Template // 自定义格式(text/template), 输入为FormatData. 若不为nil, 则忽略Separator和Reverse
+}
+
+// 预定义的样式.
+// 本库只翻译省级名称(省, 直辖市, 特别行政区, 以及直辖市的市), 不提供市和区的英文名称和拼音,
+// 因此StyleProvinceEnglish和StyleProvincePinyin不是完整的英文或拼音地址.
+var (
+	StyleFull            = Style{Names: NameFull}                                            // 浙江省杭州市西湖区
+	StyleShort           = Style{Names: NameShort, Separator: " "}                           // 浙江 杭州 西湖
+	StyleProvinceEnglish = Style{Names: NameProvinceEnglish, Separator: ", ", Reverse: true} // 西湖区, 杭州市, Zhejiang
+	StyleProvincePinyin  = Style{Names: NameProvincePinyin, Separator: " "}                  // Zhejiang Sheng 杭州市 西湖区
+)
+
+// 自定义格式(Style.Template)的输入
+type FormatData struct {
+	Province     string // 按Style.Names输出的省名
+	City         string // 市名. 直辖市去重时为""
+	District     string // 区名
+	ProvinceCode string
+	CityCode     string
+	DistrictCode string
+	Municipality bool   // 是否为直辖市
+	Detail       string // 详细地址
+	Address      string // 按Separator和Reverse拼接的省市区名称(不含详细地址)
+}
+
+// 按样式输出地址. 下级的编码可以省略上级, 例如AddressCodes{DistrictCode: "CN033001012"}.
+// 例: Format(AddressCodes{"CN033000000", "CN033001000", "CN033001012"}, StyleShort) -> "浙江 杭州 西湖"
+// 若编码不存在, 则返回ErrNotFound; 若编码的级别不对, 或者上下级之间不存在管辖关系, 则返回ErrInconsistent.
+func Format(codes AddressCodes, style Style) (string, error) {
+	return FormatDetail(codes, "", style)
+}
+
+// 按样式输出地址和详细地址. 详细地址追加在省市区名称之后(Reverse时在之前), 自定义格式中为{{.Detail}}.
+// 例: FormatDetail(codes, "文三路100号", StyleFull) -> "浙江省杭州市西湖区文三路100号"
+func FormatDetail(codes AddressCodes, detail string, style Style) (string, error) {
+	data, err := newFormatData(codes, detail, style)
+	if err != nil {
+		return "", err
+	}
+	if style.Template != nil {
+		var b strings.Builder
+		if err := style.Template.Execute(&b, data); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	if detail == "" {
+		return data.Address, nil
+	}
+	if style.Reverse {
+		return detail + style.Separator + data.Address, nil
+	}
+	return data.Address + style.Separator + detail, nil
+}
+
+// 校验编码并补全上级, 然后按样式输出名称
+func newFormatData(codes AddressCodes, detail string, style Style) (FormatData, error) {
+	levels := []Level{LevelProvince, LevelCity, LevelDistrict}
+	list := []string{codes.ProvinceCode, codes.CityCode, codes.DistrictCode}
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i] == "" {
+			continue
+		}
+		item, ok := libItems[list[i]]
+		if !ok || list[i] == ROOT {
+			return FormatData{}, lookupError(levels[i], list[i], ErrNotFound)
+		}
+		if item.level != levels[i] {
+			return FormatData{}, lookupError(levels[i], list[i], ErrInconsistent)
+		}
+		if i == 0 {
+			continue
+		}
+		if list[i-1] == "" {
+			list[i-1] = item.parent
+		} else if list[i-1] != item.parent {
+			return FormatData{}, lookupError(levels[i], list[i], ErrInconsistent)
+		}
+	}
+	if list[0] == "" {
+		return FormatData{}, lookupError("", "", ErrNotFound)
+	}
+
+	data := FormatData{
+		ProvinceCode: list[0],
+		CityCode:     list[1],
+		DistrictCode: list[2],
+		Municipality: isMunicipality(list[0]),
+		Detail:       detail,
+	}
+	names := make([]string, 0, 3)
+	for i, code := range list {
+		if code == "" {
+			continue
+		}
+		// 直辖市的市名与省名相同
+		if i == 1 && data.Municipality && !style.KeepMunicipalityCity && libItems[code].name == libItems[list[0]].name {
+			continue
+		}
+		name := style.Names.name(code)
+		names = append(names, name)
+		switch i {
+		case 0:
+			data.Province = name
+		case 1:
+			data.City = name
+		case 2:
+			data.District = name
+		}
+	}
+	if style.Reverse {
+		for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+			names[i], names[j] = names[j], names[i]
+		}
+	}
+	data.Address = strings.Join(names, style.Separator)
+	return data, nil
+}
+
+// 按样式输出编码对应的名称
+func (s NameStyle) name(code string) string {
+	item := libItems[code]
+	switch s {
+	case NameShort:
+		return libShortNames[code]
+	case NameProvinceEnglish, NameProvincePinyin:
+		if name, ok := libTranslations[translationLanguages[s]][code]; ok {
+			return name
+		}
+	}
+	return item.name
+}
+
+// 省级名称的英文名称和拼音的数据文件(可选).
+// 格式: 第1列为编码, 第2列为名称. 只能包括省级编码(参考isProvinceLevel), 可以只包括部分编码, 其它编码输出标准名称.
+const (
+	dataNameEnglish = "names_en.data"
+	dataNamePinyin  = "names_pinyin.data"
+)
+
+// 名称的样式 -> 语言
+var translationLanguages = map[NameStyle]string{NameProvinceEnglish: "en", NameProvincePinyin: "pinyin"}
+
+// 语言 -> 数据文件
+var translationFiles = map[string]string{"en": dataNameEnglish, "pinyin": dataNamePinyin}
+
+// 其它语言的名称: 语言 -> 编码 -> 名称
+var libTranslations = make(map[string]map[string]string)
+
+// 从数据文件夹加载省级名称的其它语言的名称.
+// 数据文件不存在时不报错. 若包括市或区(直辖市的市除外)的编码, 则返回ErrDataFormat.
+func loadTranslations(dataPath string, items map[string]*libItem, ptLibTranslations *map[string]map[string]string) error {
+	for _, language := range sortedKeys(translationFiles) {
+		filePath := path.Join(dataPath, translationFiles[language])
+		if _, err := os.Stat(filePath); err != nil && os.IsNotExist(err) {
+			continue
+		}
+		lines, err := readLines(filePath)
+		if err != nil {
+			return err
+		}
+		names := make(map[string]string)
+		for i, line := range lines {
+			row := strings.Split(line, "\t")
+			if len(row) != 2 || !isAllDigitAbc(row[0]) || strings.TrimSpace(row[1]) == "" {
+				detail := fmt.Sprintf("row = %s", row)
+				return &DataError{filePath, i + 1, detail, ErrDataFormat}
+			}
+			if _, ok := items[row[0]]; ok && !isProvinceLevel(items, row[0]) {
+				detail := fmt.Sprintf("only province names can be translated, code = %s", row[0])
+				return &DataError{filePath, i + 1, detail, ErrDataFormat}
+			}
+			names[row[0]] = row[1]
+		}
+		(*ptLibTranslations)[language] = names
+	}
+	return nil
+}
+
+// 判断编码是否为省级: 省, 或者直辖市的市(与省同名, 类型也是直辖市)
+func isProvinceLevel(items map[string]*libItem, code string) bool {
+	item := items[code]
+	return item.level == LevelProvince || item.kind == KindMunicipality
+}
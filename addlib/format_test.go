@@ -0,0 +1,148 @@
+package addlib
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"text/template"
+)
+
+func TestFormat(t *testing.T) {
+	xihu := AddressCodes{"CN033000000", "CN033001000", "CN033001012"}
+	dongcheng := AddressCodes{"CN003000000", "CN003001000", "CN003001004"}
+	tests := []struct {
+		codes    AddressCodes
+		style    Style
+		expected string
+	}{
+		{xihu, StyleFull, "浙江省杭州市西湖区"},
+		{xihu, StyleShort, "浙江 杭州 西湖"},
+		{xihu, Style{Names: NameFull, Separator: "/"}, "浙江省/杭州市/西湖区"},
+		{xihu, Style{Names: NameShort, Separator: "-", Reverse: true}, "西湖-杭州-浙江"},
+		{AddressCodes{DistrictCode: "CN033001012"}, StyleFull, "浙江省杭州市西湖区"},
+		{AddressCodes{ProvinceCode: "CN033000000", CityCode: "CN033001000"}, StyleShort, "浙江 杭州"},
+		{dongcheng, StyleFull, "北京市东城区"},
+		{dongcheng, StyleShort, "北京 东城"},
+		{dongcheng, Style{KeepMunicipalityCity: true}, "北京市北京市东城区"},
+		// 只翻译省级名称, 市和区输出标准名称
+		{xihu, StyleProvinceEnglish, "西湖区, 杭州市, Zhejiang"},
+		{dongcheng, StyleProvinceEnglish, "东城区, Beijing"},
+		{dongcheng, Style{Names: NameProvinceEnglish, Separator: ", ", Reverse: true, KeepMunicipalityCity: true}, "东城区, Beijing, Beijing"},
+		{xihu, StyleProvincePinyin, "Zhejiang Sheng 杭州市 西湖区"},
+	}
+	for _, test := range tests {
+		got, err := Format(test.codes, test.style)
+		if err != nil || got != test.expected {
+			t.Errorf("codes = %v, expected: %s, got: %s, %v", test.codes, test.expected, got, err)
+		}
+	}
+
+	errorTests := []struct {
+		codes    AddressCodes
+		expected error
+	}{
+		{AddressCodes{}, ErrNotFound},
+		{AddressCodes{DistrictCode: "foo"}, ErrNotFound},
+		{AddressCodes{ProvinceCode: "CN015000000", CityCode: "CN033001000"}, ErrInconsistent},
+		{AddressCodes{CityCode: "CN033000000"}, ErrInconsistent},
+		{AddressCodes{ProvinceCode: ROOT}, ErrNotFound},
+	}
+	for _, test := range errorTests {
+		if _, err := Format(test.codes, StyleFull); !errors.Is(err, test.expected) {
+			t.Errorf("codes = %v, expected: %v, got: %v", test.codes, test.expected, err)
+		}
+	}
+}
+
+func TestFormatDetail(t *testing.T) {
+	codes := AddressCodes{"CN033000000", "CN033001000", "CN033001012"}
+	tmpl := template.Must(template.New("label").Parse("{{.Province}}{{.City}}\n{{.District}} {{.Detail}} ({{.DistrictCode}})"))
+	tests := []struct {
+		style    Style
+		expected string
+	}{
+		{StyleFull, "浙江省杭州市西湖区文三路100号"},
+		{StyleShort, "浙江 杭州 西湖 文三路100号"},
+		{Style{Names: NameShort, Separator: ", ", Reverse: true}, "文三路100号, 西湖, 杭州, 浙江"},
+		{Style{Names: NameShort, Template: tmpl}, "浙江杭州\n西湖 文三路100号 (CN033001012)"},
+		{Style{Template: template.Must(template.New("").Parse("{{.Address}}|{{.Detail}}"))}, "浙江省杭州市西湖区|文三路100号"},
+	}
+	for _, test := range tests {
+		got, err := FormatDetail(codes, "文三路100号", test.style)
+		if err != nil || got != test.expected {
+			t.Errorf("expected: %s, got: %s, %v", test.expected, got, err)
+		}
+	}
+
+	bad := template.Must(template.New("").Parse("{{.Foo}}"))
+	if _, err := FormatDetail(codes, "", Style{Template: bad}); err == nil {
+		t.Errorf("expected template error, got: nil")
+	}
+}
+
+func TestFormatTranslations(t *testing.T) {
+	defer Init("lib.add")
+	dataPath := writeTestData(t, map[string]string{
+		dataProvince:    "P1\t浙江省\nP2\t北京市\n",
+		dataCity:        "P1\tC1\t杭州市\nP2\tC2\t北京市\n",
+		dataDistrict:    "C1\tD1\t西湖区\nC2\tD2\t东城区\n",
+		dataNameEnglish: "P1\tZhejiang\nP2\tBeijing\nC2\tBeijing City\n",
+		dataNamePinyin:  "P1\tZhejiang Sheng\n",
+	})
+	if err := Init(dataPath); err != nil {
+		t.Fatal(err)
+	}
+	check := func() {
+		tests := []struct {
+			codes    AddressCodes
+			style    Style
+			expected string
+		}{
+			{AddressCodes{DistrictCode: "D1"}, StyleProvinceEnglish, "西湖区, 杭州市, Zhejiang"},
+			{AddressCodes{DistrictCode: "D2"}, StyleProvinceEnglish, "东城区, Beijing"},
+			{AddressCodes{DistrictCode: "D2"}, Style{Names: NameProvinceEnglish, Separator: ", ", Reverse: true,
+				KeepMunicipalityCity: true}, "东城区, Beijing City, Beijing"},
+			{AddressCodes{DistrictCode: "D1"}, StyleProvincePinyin, "Zhejiang Sheng 杭州市 西湖区"},
+		}
+		for _, test := range tests {
+			got, err := Format(test.codes, test.style)
+			if err != nil || got != test.expected {
+				t.Errorf("codes = %v, expected: %s, got: %s, %v", test.codes, test.expected, got, err)
+			}
+		}
+	}
+	check()
+
+	// 快照包括其它语言的名称
+	var buf bytes.Buffer
+	WriteSnapshot(&buf)
+	Init("lib.add")
+	if err := ReadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	check()
+
+	dataPath = writeTestData(t, map[string]string{
+		dataProvince:    "P1\t浙江省\n",
+		dataCity:        "P1\tC1\t杭州市\n",
+		dataDistrict:    "C1\tD1\t西湖区\n",
+		dataNameEnglish: "P1\n",
+	})
+	if err := Init(dataPath); !errors.Is(err, ErrDataFormat) {
+		t.Errorf("expected: %v, got: %v", ErrDataFormat, err)
+	}
+
+	// 不能翻译市和区
+	dataPath = writeTestData(t, map[string]string{
+		dataProvince:   "P1\t浙江省\n",
+		dataCity:       "P1\tC1\t杭州市\n",
+		dataDistrict:   "C1\tD1\t西湖区\n",
+		dataNamePinyin: "P1\tZhejiang Sheng\nC1\tHangzhou Shi\n",
+	})
+	if err := Init(dataPath); !errors.Is(err, ErrDataFormat) {
+		t.Errorf("expected: %v, got: %v", ErrDataFormat, err)
+	}
+	if problems := Validate(dataPath); len(problems) != 1 || !errors.Is(problems[0], ErrDataFormat) {
+		t.Errorf("expected 1 problem, got: %v", problems)
+	}
+}
@@ -0,0 +1,209 @@
+package addlib
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// 分组方案: 内置的七大地理分区.
+// 其它方案(例如销售大区)可以写在数据文件中, 或者用DefineGroup/LoadGroups添加.
+const SchemeGeo = "geo"
+
+// 分组的数据文件(可选).
+// 格式: 第1列为方案名称(字母数字), 第2列为分组名称, 第3列为成员编码(省或市).
+const dataGroup = "groups.data"
+
+// 一个分组方案. 分组按照定义的顺序保存.
+type groupScheme struct {
+	groups  []string
+	members map[string][]string
+}
+
+// 分组方案: 方案名称 -> 方案
+var libGroups = make(map[string]*groupScheme)
+
+// 从数据文件加载分组.
+// 数据文件不存在时不报错.
+func loadGroups(filePath string, items map[string]*libItem, ptLibGroups *map[string]*groupScheme) error {
+	if _, err := os.Stat(filePath); err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	lines, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+	for i, line := range lines {
+		lineNo := i + 1
+		row := strings.Split(line, "\t")
+		if len(row) != 3 || !isAllDigitAbc(row[0]) || row[1] == "" || !isAllDigitAbc(row[2]) {
+			detail := fmt.Sprintf("row = %s", row)
+			return &DataError{filePath, lineNo, detail, ErrDataFormat}
+		}
+		err := addGroupMember(items, ptLibGroups, row[0], row[1], row[2])
+		if err != nil {
+			return &DataError{filePath, lineNo, err.Error(), ErrInconsistent}
+		}
+	}
+	return nil
+}
+
+// 把code添加到分组中. 方案或分组不存在时自动创建, 已经是成员时忽略.
+func addGroupMember(items map[string]*libItem, ptLibGroups *map[string]*groupScheme,
+	scheme string, group string, code string) error {
+
+	if _, ok := items[code]; !ok || code == ROOT {
+		return lookupError("", code, ErrNotFound)
+	}
+	s := ensureGroup(*ptLibGroups, scheme, group)
+	if !slices.Contains(s.members[group], code) {
+		s.members[group] = append(s.members[group], code)
+	}
+	return nil
+}
+
+// 输出分组所在的方案. 方案或分组不存在时自动创建.
+func ensureGroup(groups map[string]*groupScheme, scheme string, group string) *groupScheme {
+	s, ok := groups[scheme]
+	if !ok {
+		s = &groupScheme{make([]string, 0), make(map[string][]string)}
+		groups[scheme] = s
+	}
+	if _, ok := s.members[group]; !ok {
+		s.groups = append(s.groups, group)
+		s.members[group] = make([]string, 0)
+	}
+	return s
+}
+
+// 复制所有分组方案, 用于加载失败时保留原来的分组
+func cloneGroups(groups map[string]*groupScheme) map[string]*groupScheme {
+	result := make(map[string]*groupScheme, len(groups))
+	for scheme, s := range groups {
+		members := make(map[string][]string, len(s.members))
+		for group, codes := range s.members {
+			members[group] = append(make([]string, 0, len(codes)), codes...)
+		}
+		result[scheme] = &groupScheme{append(make([]string, 0, len(s.groups)), s.groups...), members}
+	}
+	return result
+}
+
+// 加载额外的分组数据文件, 格式与groups.data相同.
+// 已存在的分组会追加新的成员, 已经是成员的编码忽略, 因此重复加载同一个文件不会重复添加.
+// 若加载失败, 则保留原来的分组.
+// 注意: Init会重置所有分组, 因此需要在Init之后加载.
+func LoadGroups(filePath string) error {
+	if _, err := os.Stat(filePath); err != nil {
+		return err
+	}
+	customMu.Lock()
+	defer customMu.Unlock()
+	groups := cloneGroups(libGroups)
+	if err := loadGroups(filePath, libItems, &groups); err != nil {
+		return err
+	}
+	libGroups = groups
+	return nil
+}
+
+// 自定义分组. 若分组已存在, 则覆盖其成员, 分组的顺序不变.
+// 输入: scheme - 方案名称, group - 分组名称, codes - 成员编码. codes可以为空, 此时分组没有成员.
+func DefineGroup(scheme string, group string, codes []string) error {
+	if scheme == "" || group == "" {
+		return fmt.Errorf("%w: empty scheme or group name", ErrBadInput)
+	}
+	members := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if _, ok := libItems[code]; !ok || code == ROOT {
+			return lookupError("", code, ErrNotFound)
+		}
+		if !slices.Contains(members, code) {
+			members = append(members, code)
+		}
+	}
+	customMu.Lock()
+	defer customMu.Unlock()
+	ensureGroup(libGroups, scheme, group).members[group] = members
+	return nil
+}
+
+// 输出所有分组方案的名称
+func Schemes() []string {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	schemes := make([]string, 0)
+	for scheme := range libGroups {
+		schemes = append(schemes, scheme)
+	}
+	return schemes
+}
+
+// 输出方案中的所有分组名称
+// 若方案不存在, 则返回空[]
+func Groups(scheme string) []string {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	if s, ok := libGroups[scheme]; ok {
+		return append(make([]string, 0), s.groups...)
+	}
+	return make([]string, 0)
+}
+
+// 输出分组的成员编码(省或市)
+// 例: Members(SchemeGeo, "华北") -> [CN003000000 CN028000000 CN010000000 ...]
+func Members(scheme string, group string) []string {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	if s, ok := libGroups[scheme]; ok {
+		return append(make([]string, 0), s.members[group]...)
+	}
+	return make([]string, 0)
+}
+
+// 输入编码, 输出它在指定方案中所属的分组
+// 成员的后代也属于该分组, 例如: 杭州市属于"华东"(因为浙江省属于"华东").
+// 一个编码可以属于同一方案的多个分组.
+func RegionGroup(code string, scheme string) []string {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	return regionGroup(code, scheme)
+}
+
+// 同RegionGroup, 调用方需要持有customMu
+func regionGroup(code string, scheme string) []string {
+	groups := make([]string, 0)
+	s, ok := libGroups[scheme]
+	if !ok {
+		return groups
+	}
+	ancestors := make(map[string]bool)
+	if _, ok := libItems[code]; ok {
+		for c := code; c != ROOT; c = libItems[c].parent {
+			ancestors[c] = true
+		}
+	}
+	for _, group := range s.groups {
+		for _, member := range s.members[group] {
+			if ancestors[member] {
+				groups = append(groups, group)
+				break
+			}
+		}
+	}
+	return groups
+}
+
+// 输入编码, 输出它在所有方案中所属的分组: 方案名称 -> 分组列表
+func RegionGroups(code string) map[string][]string {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	result := make(map[string][]string)
+	for scheme := range libGroups {
+		if groups := regionGroup(code, scheme); len(groups) > 0 {
+			result[scheme] = groups
+		}
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package addlib
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestRegionGroup(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{GetCode("浙江", "", ""), "华东"},
+		{GetCode("", "杭州", "西湖"), "华东"},
+		{GetCode("", "北京", ""), "华北"},
+		{GetCode("", "乌鲁木齐", ""), "西北"},
+		{GetCode("香港", "", ""), "华南"},
+	}
+
+	for _, tt := range tests {
+		got := RegionGroup(tt.in, SchemeGeo)
+		if len(got) != 1 || got[0] != tt.expected {
+			t.Errorf("expected: [%s], got: %s", tt.expected, got)
+		}
+	}
+	if got := RegionGroup("foo", SchemeGeo); len(got) != 0 {
+		t.Errorf("expected: [], got: %s", got)
+	}
+}
+
+func TestMembers(t *testing.T) {
+	if got := len(Groups(SchemeGeo)); got != 7 {
+		t.Errorf("expected: 7, got: %d", got)
+	}
+	total := 0
+	for _, group := range Groups(SchemeGeo) {
+		total += len(Members(SchemeGeo, group))
+	}
+	if total != 34 {
+		t.Errorf("expected: 34, got: %d", total)
+	}
+	if got := len(Members(SchemeGeo, "华中")); got != 3 {
+		t.Errorf("expected: 3, got: %d", got)
+	}
+}
+
+func TestDefineGroup(t *testing.T) {
+	hz, nb, js := GetCode("", "杭州", ""), GetCode("", "宁波", ""), GetCode("江苏", "", "")
+	if err := DefineGroup("sales", "东一区", []string{hz, js}); err != nil {
+		t.Fatal(err)
+	}
+	if err := DefineGroup("sales", "东二区", []string{nb, js}); err != nil {
+		t.Fatal(err)
+	}
+	defer delete(libGroups, "sales")
+
+	if got := RegionGroup(GetCode("", "南京", ""), "sales"); len(got) != 2 {
+		t.Errorf("expected: 2 groups, got: %s", got)
+	}
+	if got := RegionGroups(GetCode("", "杭州", "西湖")); len(got) != 2 || got["sales"][0] != "东一区" {
+		t.Errorf("unexpected groups: %v", got)
+	}
+	if err := DefineGroup("sales", "东一区", []string{nb}); err != nil {
+		t.Fatal(err)
+	}
+	if got := Members("sales", "东一区"); len(got) != 1 || got[0] != nb {
+		t.Errorf("expected: [%s], got: %s", nb, got)
+	}
+	// 重新定义的分组保持原来的顺序
+	if got := Groups("sales"); len(got) != 2 || got[0] != "东一区" || got[1] != "东二区" {
+		t.Errorf("expected: [东一区 东二区], got: %s", got)
+	}
+	if err := DefineGroup("sales", "bad", []string{"foo"}); err == nil {
+		t.Errorf("expected error for unknown code")
+	}
+	// 没有成员的新分组
+	if err := DefineGroup("sales", "东三区", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := Groups("sales"); len(got) != 3 || got[2] != "东三区" || len(Members("sales", "东三区")) != 0 {
+		t.Errorf("expected empty group 东三区, got: %s", got)
+	}
+	if err := DefineGroup("", "东四区", nil); !errors.Is(err, ErrBadInput) {
+		t.Errorf("expected: %v, got: %v", ErrBadInput, err)
+	}
+}
+
+func TestLoadGroups(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "sales.data")
+	data := "sales\t浙北\t" + GetCode("", "杭州", "") + "\nsales\t浙北\t" + GetCode("", "湖州", "") + "\n"
+	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadGroups(filePath); err != nil {
+		t.Fatal(err)
+	}
+	defer delete(libGroups, "sales")
+
+	if got := Members("sales", "浙北"); len(got) != 2 {
+		t.Errorf("expected: 2, got: %d", len(got))
+	}
+	// 重复加载不重复添加成员
+	if err := LoadGroups(filePath); err != nil {
+		t.Fatal(err)
+	}
+	if got := Members("sales", "浙北"); len(got) != 2 {
+		t.Errorf("expected: 2, got: %d", len(got))
+	}
+	// 加载失败时保留原来的分组
+	badPath := path.Join(t.TempDir(), "bad.data")
+	data = "sales\t浙南\t" + GetCode("", "温州", "") + "\nsales\t浙北\t" + GetCode("", "嘉兴", "") + "\nsales\t浙北\tfoo\n"
+	if err := os.WriteFile(badPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadGroups(badPath); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("expected error for unknown code, got: %v", err)
+	}
+	if got := Groups("sales"); len(got) != 1 || len(Members("sales", "浙北")) != 2 {
+		t.Errorf("expected groups unchanged, got: %s %s", got, Members("sales", "浙北"))
+	}
+	if err := LoadGroups(path.Join(t.TempDir(), "foo.data")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
@@ -17,6 +17,8 @@ type libItem struct {
 	name     string
 	parent   string
 	children []string
+	level    Level
+	kind     Kind
 }
 
 // 地址库
@@ -26,16 +28,13 @@ var libItems = make(map[string]*libItem)
 // 使用场景: 用非标准的名称查询编码, 然后得到标准的地址名称
 var libIndex = make(map[string]string)
 
+// 有歧义的索引: 多个地址名称的前k个汉字相同, 因此在cleanIndex中被删除的key.
+// 使用场景: 查询失败时区分"不存在"和"有歧义"
+var libAmbiguous = make(map[string]bool)
+
 // 根节点, 作为"省"的父节点.
 const ROOT = "ROOT"
 
-// 统计区划级别
-const (
-	levelProvince = "province"
-	levelCity     = "city"
-	levelDistrict = "district"
-)
-
 // 数据文件的名称
 const (
 	dataProvince = "provinces.data"
@@ -57,43 +56,85 @@ func init() {
 // 初始化
 func Init(dataPath string) error {
 	dataFiles := []string{dataProvince, dataCity, dataDistrict}
-	if !checkData(dataPath, dataFiles) {
-		return errors.New("miss data files")
+	if err := checkData(dataPath, dataFiles); err != nil {
+		return err
 	}
-	libIndexCache := make(map[string]string) // 用于记录key对应的标准地址名称
-	// 初始化全局变量(防止Init函数被多次调用)
-	libItems = make(map[string]*libItem)
-	libIndex = make(map[string]string)
+	// 先加载到局部变量, 全部成功后再替换全局变量.
+	// 这样Init可以被多次调用, 且加载失败时保留原来的地址库.
+	items := make(map[string]*libItem)
+	index := make(map[string]string)
+	indexCache := make(map[string]string) // 用于记录key对应的标准地址名称
+	ambiguous := make(map[string]bool)
+	sets := make(map[RegionSet]map[string]bool)
+	groups := make(map[string]*groupScheme)
+	gbCodes := make(map[string]string)
+	translations := make(map[string]map[string]string)
+	overrides := make(map[string]string)
 	for _, file := range dataFiles {
-		err := loadSingleDataFile(path.Join(dataPath, file), &libItems, &libIndex, &libIndexCache)
+		err := loadSingleDataFile(path.Join(dataPath, file), &items, &index, &indexCache)
 		if err != nil {
 			return err
 		}
 	}
+	// 区域集合和分组依赖地址数据, 需要在地址数据之后加载.
+	err := loadRegionSets(path.Join(dataPath, dataRegionSet), items, &sets)
+	if err != nil {
+		return err
+	}
+	err = loadGroups(path.Join(dataPath, dataGroup), items, &groups)
+	if err != nil {
+		return err
+	}
+	err = loadGBCodes(path.Join(dataPath, dataGBCode), &gbCodes)
+	if err != nil {
+		return err
+	}
+	err = loadTranslations(dataPath, items, &translations)
+	if err != nil {
+		return err
+	}
+	err = loadShortNames(path.Join(dataPath, dataShortName), &overrides)
+	if err != nil {
+		return err
+	}
 
 	// 删除autoIndex产生的空索引.
-	cleanIndex(&libIndex)
+	cleanIndex(&index, &ambiguous)
+	shortNames := buildShortNames(items, overrides)
+	indexShortNames(items, index, shortNames)
+
+	libItems, libIndex, libAmbiguous = items, index, ambiguous
+	libShortNames = shortNames
+	libNames, libNameSize = buildMatchNames(items, index, shortNames)
+	customMu.Lock()
+	libSets, libGroups = sets, groups
+	customMu.Unlock()
+	libGBCodes = gbCodes
+	libTranslations = translations
 	return nil
 }
 
-// 按行异步读取单个数据文件
+// 按行读取单个数据文件
 // 初始化LibItems和LibIndex
 func loadSingleDataFile(filePath string, ptLibItems *map[string]*libItem,
 	ptLibIndex *map[string]string, ptLibIndexCache *map[string]string) error {
 
-	lines := make(chan string, 1)
-	go readLines(filePath, lines)
-	for line := range lines {
+	lines, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+	for i, line := range lines {
+		lineNo := i + 1
 		row := strings.Split(line, "\t")
 		level, _ := parseLevelFromFilePath(filePath)
-		if !checkRow(row, level) {
-			msg := fmt.Sprintf("wrong data format, level = %s, row = %s", level, row)
-			return errors.New(msg)
+		if problem := rowProblem(row, level); problem != "" {
+			detail := fmt.Sprintf("level = %s, %s", level, problem)
+			return &DataError{filePath, lineNo, detail, ErrDataFormat}
 		}
 		initLibItems(ptLibItems, row, level)
 		err := initLibIndex(ptLibIndex, ptLibIndexCache, row, level)
 		if err != nil {
-			return err
+			return dataError(filePath, lineNo, err)
 		}
 	}
 	return nil
@@ -102,37 +143,49 @@ func loadSingleDataFile(filePath string, ptLibItems *map[string]*libItem,
 // 检查数据文件是否存在
 // 输入: filePath - 数据的文件夹名
 // 输入: dataFiles - 数据的文件名列表
-func checkData(filePath string, dataFiles []string) bool {
+// 若文件不存在, 则返回DataError, 其中Path为缺失的文件.
+func checkData(filePath string, dataFiles []string) error {
 	for _, file := range dataFiles {
 		_, err := os.Stat(path.Join(filePath, file))
 		if err != nil && os.IsNotExist(err) {
-			return false
+			return &DataError{path.Join(filePath, file), 0, "missing data file", os.ErrNotExist}
 		}
 	}
-	return true
+	return nil
 }
 
 // 输入行, 检查数据的格式是否正确
 // 规则如下:
 // 1. dataProvince: 第1列为自身的编码(字母数字), 第2列为地址名称(仅汉字).
 // 2. dataCity和dataDistrict: 第1列为parent编码, 第2列为自身的编码, 第3列为地址名称.
-func checkRow(row []string, level string) bool {
-	if level == levelProvince {
-		if len(row) != 2 {
-			return false
-		}
-		if !(isAllDigitAbc(row[0]) && isAllHanChar(row[1])) {
-			return false
-		}
-	} else if level == levelCity || level == levelDistrict {
-		if len(row) != 3 {
-			return false
-		}
-		if !(isAllDigitAbc(row[0]) && isAllDigitAbc(row[1]) && isAllHanChar(row[2])) {
-			return false
+func checkRow(row []string, level Level) bool {
+	return rowProblem(row, level) == ""
+}
+
+// 输入行, 输出checkRow不通过的原因, 例如列数不对或名称不是汉字.
+// 若格式正确, 则返回"".
+func rowProblem(row []string, level Level) string {
+	columns := 0
+	switch level {
+	case LevelProvince:
+		columns = 2
+	case LevelCity, LevelDistrict:
+		columns = 3
+	default:
+		return ""
+	}
+	if len(row) != columns {
+		return fmt.Sprintf("expected %d columns, got %d, row = %s", columns, len(row), row)
+	}
+	for _, code := range row[:columns-1] {
+		if code == "" || !isAllDigitAbc(code) {
+			return fmt.Sprintf("code is not alphanumeric, code = %s", code)
 		}
 	}
-	return true
+	if name := row[columns-1]; name == "" || !isAllHanChar(name) {
+		return fmt.Sprintf("name is not all Han characters, name = %s", name)
+	}
+	return ""
 }
 
 // 判断字符串是否全部汉字.
@@ -161,26 +214,40 @@ func isAllDigitAbc(str string) bool {
 // 注意:
 // 1. 数据文件(provinces.data)由于没有父节点, 其第0列为code, 第1列为name.
 // 2. 数据文件(cities.data和districts.data), 其第0列为parent, 第1列为code, 第2列为name.
-func initLibItems(ptLibItems *map[string]*libItem, row []string, level string) {
-	if level == levelProvince {
+func initLibItems(ptLibItems *map[string]*libItem, row []string, level Level) {
+	if level == LevelProvince {
 		updateParent(ptLibItems, ROOT, row[0])
 		updateSelf(ptLibItems, ROOT, row[0], row[1])
+		setLevelKind(ptLibItems, row[0], level)
 	} else {
 		updateParent(ptLibItems, row[0], row[1])
 		updateSelf(ptLibItems, row[0], row[1], row[2])
+		setLevelKind(ptLibItems, row[1], level)
+	}
+}
+
+// 设置节点的级别和类型.
+// 类型由名称推断, 因此需要在updateSelf之后调用.
+func setLevelKind(ptLibItems *map[string]*libItem, self string, level Level) {
+	item := (*ptLibItems)[self]
+	parentKind := KindUnknown
+	if parent, ok := (*ptLibItems)[item.parent]; ok {
+		parentKind = parent.kind
 	}
+	item.level = level
+	item.kind = kindOf(level, item.name, parentKind)
 }
 
 // 通过文件名确定地址数据的统计区划级别.
 // 一共三种级别: PROVINCE, CITY, DISTRICT.
-func parseLevelFromFilePath(filePath string) (string, error) {
+func parseLevelFromFilePath(filePath string) (Level, error) {
 	switch base := path.Base(filePath); base {
 	case dataProvince:
-		return levelProvince, nil
+		return LevelProvince, nil
 	case dataCity:
-		return levelCity, nil
+		return LevelCity, nil
 	case dataDistrict:
-		return levelDistrict, nil
+		return LevelDistrict, nil
 	default:
 		msg := fmt.Sprintf("incorrect path, filePath = %s", filePath)
 		return "", errors.New(msg)
@@ -196,16 +263,16 @@ func parseLevelFromFilePath(filePath string) (string, error) {
 //    若key的长度小于旧项标准名称的长度, 则把key删除. 然后对新项和旧项重新建索引, 此时k值加1.
 // 4. 注意: 旧项的名字不能是新项名字的子字符串(否则算法需要修改).
 func initLibIndex(ptLibIndex *map[string]string, ptLibIndexCache *map[string]string,
-	row []string, level string) error {
+	row []string, level Level) error {
 
 	var err error
-	if level == levelProvince {
+	if level == LevelProvince {
 		// 注意: 省的key名为: <level>-<省名>. 例如: PROVINCE-北京.
-		err = autoIndex(ptLibIndex, ptLibIndexCache, level, row[0], row[1], 2)
-	} else if level == levelCity {
+		err = autoIndex(ptLibIndex, ptLibIndexCache, string(level), row[0], row[1], 2)
+	} else if level == LevelCity {
 		// 市的key名为: <level>-<市名>. 例如: CITY-杭州.
-		err = autoIndex(ptLibIndex, ptLibIndexCache, level, row[1], row[2], 2)
-	} else if level == levelDistrict {
+		err = autoIndex(ptLibIndex, ptLibIndexCache, string(level), row[1], row[2], 2)
+	} else if level == LevelDistrict {
 		// 区的key名为: <市编码>-<区名>. 例如: CN033001000-西湖区.
 		err = autoIndex(ptLibIndex, ptLibIndexCache, row[0], row[1], row[2], 2)
 	}
@@ -231,12 +298,14 @@ func autoIndex(ptLibIndex *map[string]string, ptLibIndexCache *map[string]string
 		keySize := getKeySize(key) + 1
 		// 数据有重复
 		if keySize > len([]rune(name)) {
-			fmt.Println(key, initKeySize)
-			return errors.New("data has identical items")
+			msg := fmt.Sprintf("data has identical items, key = %s", key)
+			return errors.New(msg)
 		}
 		// 如果旧项key的长度无法再增加, 说明旧项是新项的子字符串, 则返回错误.
-		if n := len([]rune((*ptLibIndex)[key])); n != 0 && keySize > n {
-			return errors.New("sub-name exists")
+		// 比较的是旧项名称(ptLibIndexCache)的长度, 不是编码的长度.
+		if n := len([]rune((*ptLibIndexCache)[key])); (*ptLibIndex)[key] != "" && keySize > n {
+			msg := fmt.Sprintf("sub-name exists, key = %s", key)
+			return errors.New(msg)
 		}
 		// 把旧项key对应的value标记为空 (仅标记删除).
 		// 暂时不能删旧项已存在的key, 防止有其它项也有前k个相同的汉字.
@@ -263,11 +332,12 @@ func autoIndex(ptLibIndex *map[string]string, ptLibIndexCache *map[string]string
 }
 
 // 删除空索引
-// 即: 删除autoIndex方法中标记为空的索引
-func cleanIndex(ptLibIndex *map[string]string) {
+// 即: 删除autoIndex方法中标记为空的索引, 并把它们记录为有歧义的索引.
+func cleanIndex(ptLibIndex *map[string]string, ptLibAmbiguous *map[string]bool) {
 	for key := range *ptLibIndex {
 		if (*ptLibIndex)[key] == "" {
 			delete(*ptLibIndex, key)
+			(*ptLibAmbiguous)[key] = true
 		}
 	}
 }
@@ -293,29 +363,32 @@ func getKeySize(key string) int {
 }
 
 // 按行读文件
-// 如果出错, 则想lines写入空字符串.
-func readLines(filePath string, lines chan string) {
+// 若文件无法打开或读取出错, 则返回DataError.
+func readLines(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		lines <- ""
-		return
+		return nil, &DataError{filePath, 0, "cannot open data file", err}
 	}
 	defer file.Close()
 
+	lines := make([]string, 0)
 	scanner := bufio.NewScanner(file)
 	scanner.Split(bufio.ScanLines)
 
 	for scanner.Scan() {
-		lines <- scanner.Text()
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &DataError{filePath, len(lines) + 1, "cannot read data file", err}
 	}
-	defer close(lines)
+	return lines, nil
 }
 
 // 给定当前节点(例如杭州市), 创建或更新父节点(例如浙江省)
 func updateParent(ptLibItems *map[string]*libItem, parent string, self string) {
 	if _, ok := (*ptLibItems)[parent]; !ok {
 		// 节点不存在则新增一个空的父节点
-		(*ptLibItems)[parent] = &libItem{parent, "", "", make([]string, 0)}
+		(*ptLibItems)[parent] = &libItem{parent, "", "", make([]string, 0), "", KindUnknown}
 	}
 	// 把self作为父节点的孩子(添加到children列表中).
 	(*ptLibItems)[parent].children = append((*ptLibItems)[parent].children, self)
@@ -327,6 +400,6 @@ func updateSelf(ptLibItems *map[string]*libItem, parent string, self string, sel
 		p.name = selfName
 		p.parent = parent
 	} else {
-		(*ptLibItems)[self] = &libItem{self, selfName, parent, make([]string, 0)}
+		(*ptLibItems)[self] = &libItem{self, selfName, parent, make([]string, 0), "", KindUnknown}
 	}
 }
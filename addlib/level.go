@@ -0,0 +1,150 @@
+package addlib
+
+import "strings"
+
+// 统计区划级别: 省, 市, 区.
+// 其取值同时作为中文索引中省和市的key前缀(参考initLibIndex).
+type Level string
+
+const (
+	LevelProvince Level = "province"
+	LevelCity     Level = "city"
+	LevelDistrict Level = "district"
+)
+
+// 行政区划类型.
+// 同一级别下的区划可以有不同的类型, 例如市级包括地级市, 自治州, 盟和地区.
+type Kind int
+
+const (
+	KindUnknown              Kind = iota // 未知
+	KindMunicipality                     // 直辖市
+	KindProvince                         // 省
+	KindAutonomousRegion                 // 自治区
+	KindSAR                              // 特别行政区
+	KindPrefectureCity                   // 地级市
+	KindAutonomousPrefecture             // 自治州
+	KindLeague                           // 盟
+	KindPrefecture                       // 地区
+	KindDirectlyAdministered             // 直辖县级行政区划
+	KindUrbanDistrict                    // 市辖区
+	KindCounty                           // 县
+	KindCountyCity                       // 县级市
+	KindAutonomousCounty                 // 自治县
+	KindBanner                           // 旗
+	KindAutonomousBanner                 // 自治旗
+	KindSpecialDistrict                  // 特区
+	KindTownship                         // 乡
+	KindTown                             // 镇
+)
+
+var kindNames = map[Kind]string{
+	KindUnknown:              "未知",
+	KindMunicipality:         "直辖市",
+	KindProvince:             "省",
+	KindAutonomousRegion:     "自治区",
+	KindSAR:                  "特别行政区",
+	KindPrefectureCity:       "地级市",
+	KindAutonomousPrefecture: "自治州",
+	KindLeague:               "盟",
+	KindPrefecture:           "地区",
+	KindDirectlyAdministered: "直辖县级行政区划",
+	KindUrbanDistrict:        "市辖区",
+	KindCounty:               "县",
+	KindCountyCity:           "县级市",
+	KindAutonomousCounty:     "自治县",
+	KindBanner:               "旗",
+	KindAutonomousBanner:     "自治旗",
+	KindSpecialDistrict:      "特区",
+	KindTownship:             "乡",
+	KindTown:                 "镇",
+}
+
+// 输出类型的中文名称, 例如: KindCountyCity -> 县级市
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return kindNames[KindUnknown]
+}
+
+// 按名称后缀推断区划类型.
+// 注意:
+// 1. 匹配时先长后短, 例如"自治县"要先于"县"匹配.
+// 2. 直辖市在数据中同时出现在省级和市级(例如: 北京市 -> 北京市), 市级项的类型由父节点决定.
+func kindOf(level Level, name string, parentKind Kind) Kind {
+	switch level {
+	case LevelProvince:
+		switch {
+		case strings.HasSuffix(name, "特别行政区"):
+			return KindSAR
+		case strings.HasSuffix(name, "自治区"):
+			return KindAutonomousRegion
+		case strings.HasSuffix(name, "省"):
+			return KindProvince
+		case strings.HasSuffix(name, "市"):
+			return KindMunicipality
+		}
+	case LevelCity:
+		switch {
+		case parentKind == KindMunicipality:
+			return KindMunicipality
+		case strings.HasSuffix(name, "直辖县级行政区划"):
+			return KindDirectlyAdministered
+		case strings.HasSuffix(name, "自治州"):
+			return KindAutonomousPrefecture
+		case strings.HasSuffix(name, "地区"):
+			return KindPrefecture
+		case strings.HasSuffix(name, "盟"):
+			return KindLeague
+		case strings.HasSuffix(name, "市"):
+			return KindPrefectureCity
+		case strings.HasSuffix(name, "县"):
+			return KindCounty
+		}
+	case LevelDistrict:
+		switch {
+		case strings.HasSuffix(name, "自治县"):
+			return KindAutonomousCounty
+		case strings.HasSuffix(name, "自治旗"):
+			return KindAutonomousBanner
+		case strings.HasSuffix(name, "特区"):
+			return KindSpecialDistrict
+		case strings.HasSuffix(name, "旗"):
+			return KindBanner
+		case strings.HasSuffix(name, "县"):
+			return KindCounty
+		case strings.HasSuffix(name, "市"):
+			return KindCountyCity
+		case strings.HasSuffix(name, "区"):
+			return KindUrbanDistrict
+		case strings.HasSuffix(name, "乡"):
+			return KindTownship
+		case strings.HasSuffix(name, "镇"):
+			return KindTown
+		}
+	}
+	return KindUnknown
+}
+
+// 按类型过滤编码列表.
+// 若kinds为空, 则原样返回.
+func filterByKind(codes []string, kinds []Kind) []string {
+	if len(kinds) == 0 {
+		return codes
+	}
+	result := make([]string, 0)
+	for _, code := range codes {
+		item, ok := libItems[code]
+		if !ok {
+			continue
+		}
+		for _, kind := range kinds {
+			if item.kind == kind {
+				result = append(result, code)
+				break
+			}
+		}
+	}
+	return result
+}
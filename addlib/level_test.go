@@ -0,0 +1,92 @@
+package addlib
+
+import "testing"
+
+func TestGetLevel(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Level
+	}{
+		{"CN033000000", LevelProvince},
+		{"CN033001000", LevelCity},
+		{"CN033001012", LevelDistrict},
+		{"foo", ""},
+	}
+
+	for _, tt := range tests {
+		if got := GetLevel(tt.in); got != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestGetKind(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Kind
+	}{
+		{GetCode("浙江", "", ""), KindProvince},
+		{GetCode("北京", "", ""), KindMunicipality},
+		{GetCode("", "北京", ""), KindMunicipality},
+		{GetCode("内蒙古", "", ""), KindAutonomousRegion},
+		{GetCode("香港", "", ""), KindSAR},
+		{GetCode("", "杭州", ""), KindPrefectureCity},
+		{GetCode("", "恩施", ""), KindAutonomousPrefecture},
+		{GetCode("", "兴安盟", ""), KindLeague},
+		{GetCode("", "阿克苏", ""), KindPrefecture},
+		{GetCode("", "杭州", "西湖"), KindUrbanDistrict},
+		{GetCode("", "杭州", "淳安"), KindCounty},
+		{GetCode("", "杭州", "建德"), KindCountyCity},
+		{GetCode("", "重庆", "石柱"), KindAutonomousCounty},
+		{GetCode("", "巴彦淖尔", "乌拉特中旗"), KindBanner},
+		{GetCode("", "呼伦贝尔", "鄂伦春"), KindAutonomousBanner},
+		{GetCode("", "六盘水", "六枝"), KindSpecialDistrict},
+		{"foo", KindUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := GetKind(tt.in); got != tt.expected {
+			t.Errorf("in: %s, expected: %s, got: %s", tt.in, tt.expected, got)
+		}
+	}
+}
+
+func TestCityCodesKind(t *testing.T) {
+	tests := []struct {
+		inProvince string
+		inKinds    []Kind
+		expected   int
+	}{
+		{"云南", []Kind{KindAutonomousPrefecture}, 8},
+		{"内蒙古", []Kind{KindLeague}, 3},
+		{"新疆", []Kind{KindPrefecture, KindAutonomousPrefecture}, 10},
+		{"浙江", []Kind{KindLeague}, 0},
+	}
+
+	for _, tt := range tests {
+		got := len(CityCodes(GetProvinceCode(tt.inProvince), tt.inKinds...))
+		if got != tt.expected {
+			t.Errorf("expected: %d, got: %d", tt.expected, got)
+		}
+	}
+}
+
+func TestDistrictCodesKind(t *testing.T) {
+	tests := []struct {
+		inCity   string
+		inKinds  []Kind
+		expected int
+	}{
+		{"杭州", []Kind{KindUrbanDistrict}, 9},
+		{"杭州", []Kind{KindCounty, KindCountyCity}, 4},
+		{"重庆", []Kind{KindAutonomousCounty}, 4},
+		{"foo", []Kind{KindCounty}, 0},
+	}
+
+	for _, tt := range tests {
+		got := len(DistrictCodes(GetCityCode(tt.inCity), tt.inKinds...))
+		if got != tt.expected {
+			t.Errorf("expected: %d, got: %d", tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package addlib
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// 规范格式的问题的详情前缀. 这类问题可以用Repair自动修复.
+const canonicalPrefix = "not canonical: "
+
+// 去掉每列首尾的空白字符
+func normalizeColumns(columns []string) []string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		result[i] = strings.TrimSpace(column)
+	}
+	return result
+}
+
+// 检查数据文件. 除了Validate的检查之外, 还包括:
+// 1. 规范格式: 多余的空白字符, 空行, 完全相同的重复行. 详情以"not canonical: "开头, 可以用Repair自动修复.
+// 2. 名称的后缀与级别不符, 即无法识别区划类型(参考kindOf). 港澳的区划没有统一的后缀, 不检查.
+// 不检查行的顺序: 文件中的顺序(同一个父节点下按名称的拼音排列)决定了Provinces, Cities等的输出顺序, 因此以文件为准.
+// 若没有问题, 则返回空[]
+func Lint(dataPath string) []*DataError {
+	rows, problems := readDataRows(dataPath, true)
+	rows, duplicates := removeDuplicateRows(rows)
+	problems = append(problems, duplicates...)
+	problems = append(problems, validateRows(dataPath, rows)...)
+
+	// 空白字符和空行. 格式错误的行已经在上面报告.
+	for _, file := range []string{dataProvince, dataCity, dataDistrict} {
+		filePath := path.Join(dataPath, file)
+		lines, err := readLines(filePath)
+		if err != nil {
+			continue
+		}
+		for i, line := range lines {
+			normalized := normalizeColumns(strings.Split(line, "\t"))
+			if strings.TrimSpace(line) == "" || strings.Join(normalized, "\t") != line {
+				problems = append(problems, &DataError{filePath, i + 1,
+					canonicalPrefix + "extra whitespace or empty line", ErrDataFormat})
+			}
+		}
+	}
+
+	// 名称的后缀. 行的顺序是省, 市, 区, 因此父节点总是先于子节点处理.
+	kinds := make(map[string]Kind)
+	underSAR := make(map[string]bool)
+	for _, row := range rows {
+		kind := kindOf(row.level, row.name, kinds[row.parent])
+		kinds[row.code] = kind
+		underSAR[row.code] = kind == KindSAR || underSAR[row.parent]
+		if kind == KindUnknown && !underSAR[row.code] {
+			detail := fmt.Sprintf("name suffix does not match level, level = %s, name = %s", row.level, row.name)
+			problems = append(problems, &DataError{row.filePath, row.line, detail, ErrInconsistent})
+		}
+	}
+	return problems
+}
+
+// 删除完全相同的重复行(仅保留第一行), 输出剩余的行和被删除的行对应的问题.
+func removeDuplicateRows(rows []dataRow) ([]dataRow, []*DataError) {
+	result := make([]dataRow, 0, len(rows))
+	problems := make([]*DataError, 0)
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		key := string(row.level) + "\t" + strings.Join(row.columns(), "\t")
+		if seen[key] {
+			problems = append(problems, &DataError{row.filePath, row.line,
+				canonicalPrefix + "duplicate row", ErrDataFormat})
+			continue
+		}
+		seen[key] = true
+		result = append(result, row)
+	}
+	return result, problems
+}
+
+// Repair的选项. 零值表示默认选项.
+type RepairOptions struct {
+	Sort bool // 按编码排序每个文件的行. 默认保持原来的顺序
+}
+
+// 把数据文件重写为规范格式:
+// 1. 去掉每列首尾的空白字符, 删除空行
+// 2. 删除完全相同的重复行
+// 3. 若opts.Sort为true, 则按编码排序. 注意: 行的顺序决定了Provinces, Cities等的输出顺序, 随附的数据同一个父节点下按名称的拼音排列.
+// 若存在无法自动修复的问题(例如编码重复或父节点不存在), 则不修改任何文件, 并返回第一个问题.
+func Repair(dataPath string, opts RepairOptions) error {
+	rows, problems := readDataRows(dataPath, true)
+	rows, _ = removeDuplicateRows(rows)
+	problems = append(problems, validateRows(dataPath, rows)...)
+	if len(problems) > 0 {
+		return problems[0]
+	}
+
+	if opts.Sort {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].code < rows[j].code
+		})
+	}
+	files := make(map[string][]string)
+	for _, file := range []string{dataProvince, dataCity, dataDistrict} {
+		files[path.Join(dataPath, file)] = make([]string, 0)
+	}
+	for _, row := range rows {
+		files[row.filePath] = append(files[row.filePath], strings.Join(row.columns(), "\t"))
+	}
+	for filePath, lines := range files {
+		if err := writeLines(filePath, lines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 把行写入文件, 每行以换行符结尾.
+// 先写入临时文件, 再替换原文件, 防止写入失败时破坏原文件.
+func writeLines(filePath string, lines []string) error {
+	data := ""
+	if len(lines) > 0 {
+		data = strings.Join(lines, "\n") + "\n"
+	}
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(data), 0644); err != nil {
+		return &DataError{filePath, 0, "cannot write data file", err}
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return &DataError{filePath, 0, "cannot write data file", err}
+	}
+	return nil
+}
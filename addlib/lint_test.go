@@ -0,0 +1,90 @@
+package addlib
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	if problems := Lint("lib.add"); len(problems) != 0 {
+		t.Errorf("expected no problems, got: %v", problems)
+	}
+
+	dataPath := writeTestData(t, map[string]string{
+		dataProvince: "P2\t江苏省\nP1\t浙江省 \n\nP1\t浙江省\n",
+		dataCity:     "P1\tC1\t杭州市\nP2\tC2\t南京省\n",
+		dataDistrict: "C1\tD1\t西湖区\n",
+	})
+	expected := []string{
+		"provinces.data:2:not canonical: extra whitespace",
+		"provinces.data:3:not canonical: extra whitespace",
+		"provinces.data:4:not canonical: duplicate row",
+		"cities.data:2:name suffix does not match level",
+	}
+
+	problems := Lint(dataPath)
+	if len(problems) != len(expected) {
+		t.Errorf("expected %d problems, got: %v", len(expected), problems)
+	}
+	for _, e := range expected {
+		found := false
+		for _, p := range problems {
+			s := fmt.Sprintf("%s:%d:%s", path.Base(p.Path), p.Line, p.Detail)
+			if strings.HasPrefix(s, e) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected problem %s, got: %v", e, problems)
+		}
+	}
+}
+
+func TestRepair(t *testing.T) {
+	dataPath := writeTestData(t, map[string]string{
+		dataProvince: "P2\t江苏省\nP1\t浙江省 \n\nP1\t浙江省\n",
+		dataCity:     "P1\tC3\t温州市\nP1\tC1\t杭州市\nP2\tC2\t南京市\n",
+		dataDistrict: "C1\tD1\t西湖区\n",
+	})
+	if err := Repair(dataPath, RepairOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if problems := Lint(dataPath); len(problems) != 0 {
+		t.Errorf("expected no problems, got: %v", problems)
+	}
+	data, _ := os.ReadFile(path.Join(dataPath, dataProvince))
+	if expected := "P2\t江苏省\nP1\t浙江省\n"; string(data) != expected {
+		t.Errorf("expected: %q, got: %q", expected, data)
+	}
+	data, _ = os.ReadFile(path.Join(dataPath, dataCity))
+	// 行的顺序不变
+	if expected := "P1\tC3\t温州市\nP1\tC1\t杭州市\nP2\tC2\t南京市\n"; string(data) != expected {
+		t.Errorf("expected: %q, got: %q", expected, data)
+	}
+
+	// 按编码排序
+	if err := Repair(dataPath, RepairOptions{Sort: true}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path.Join(dataPath, dataProvince))
+	if expected := "P1\t浙江省\nP2\t江苏省\n"; string(data) != expected {
+		t.Errorf("expected: %q, got: %q", expected, data)
+	}
+	data, _ = os.ReadFile(path.Join(dataPath, dataCity))
+	if expected := "P1\tC1\t杭州市\nP2\tC2\t南京市\nP1\tC3\t温州市\n"; string(data) != expected {
+		t.Errorf("expected: %q, got: %q", expected, data)
+	}
+
+	// 无法自动修复: 父节点不存在
+	os.WriteFile(path.Join(dataPath, dataDistrict), []byte("C9\tD1\t西湖区\n"), 0644)
+	if err := Repair(dataPath, RepairOptions{}); err == nil {
+		t.Errorf("expected error for orphan row")
+	}
+	data, _ = os.ReadFile(path.Join(dataPath, dataDistrict))
+	if string(data) != "C9\tD1\t西湖区\n" {
+		t.Errorf("expected file unchanged, got: %q", data)
+	}
+}
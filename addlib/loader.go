@@ -0,0 +1,196 @@
+package addlib
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// 地址记录: 数据加载器的输出.
+type Record struct {
+	Level  Level  // 统计区划级别. 若为"", 则按父节点的层数推断
+	Parent string // 父节点编码. 省的父节点编码为""
+	Code   string
+	Name   string
+	Line   int // 在源文件中的行号(从1开始). 若未知, 则为0
+}
+
+// 数据加载器: 从r中读取数据, 输出地址记录.
+// 记录可以是任意顺序, 但同一个父节点的孩子按记录的顺序排列.
+type Loader interface {
+	Load(r io.Reader) ([]Record, error)
+}
+
+// 函数形式的数据加载器
+type LoaderFunc func(r io.Reader) ([]Record, error)
+
+func (f LoaderFunc) Load(r io.Reader) ([]Record, error) {
+	return f(r)
+}
+
+// 数据加载器: 格式名称 -> 加载器
+// 格式名称即文件的扩展名(不含"."), 例如: json, csv, yaml
+var loaders = map[string]Loader{
+	"json": LoaderFunc(loadJSON),
+	"csv":  LoaderFunc(loadCSV),
+	"yaml": LoaderFunc(loadYAML),
+	"yml":  LoaderFunc(loadYAML),
+}
+
+// 注册数据加载器. 若格式已存在, 则覆盖.
+func RegisterLoader(format string, loader Loader) {
+	loaders[strings.ToLower(format)] = loader
+}
+
+// 从单个数据文件初始化地址库
+// 输入: filePath - 数据文件的路径, format - 数据格式(例如: json, csv, yaml). 若为"", 则按文件的扩展名选择.
+// 注意:
+// 1. 区域集合, 分组和国家标准代码只能从Init的数据文件夹加载, 用InitFile初始化后它们为空.
+// 2. 直辖市的"市辖区"和"县"会合并为一个与省同名的市, 与lib.add的约定相同(参考README"直辖市").
+func InitFile(filePath string, format string) error {
+	if format == "" {
+		format = strings.TrimPrefix(path.Ext(filePath), ".")
+	}
+	loader, ok := loaders[strings.ToLower(format)]
+	if !ok {
+		detail := fmt.Sprintf("unknown data format, format = %s", format)
+		return &DataError{filePath, 0, detail, ErrDataFormat}
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return &DataError{filePath, 0, "cannot open data file", err}
+	}
+	defer file.Close()
+
+	records, err := loader.Load(file)
+	if err != nil {
+		return dataError(filePath, 0, err)
+	}
+	return initRecords(filePath, records)
+}
+
+// 用地址记录初始化地址库. source为数据来源, 用于错误信息.
+func initRecords(source string, records []Record) error {
+	records, err := normalizeRecords(records)
+	if err != nil {
+		return dataError(source, 0, err)
+	}
+	items := make(map[string]*libItem)
+	index := make(map[string]string)
+	indexCache := make(map[string]string) // 用于记录key对应的标准地址名称
+	ambiguous := make(map[string]bool)
+	for _, r := range records {
+		row := []string{r.Code, r.Name}
+		if r.Level != LevelProvince {
+			row = []string{r.Parent, r.Code, r.Name}
+		}
+		if problem := rowProblem(row, r.Level); problem != "" {
+			detail := fmt.Sprintf("level = %s, %s", r.Level, problem)
+			return &DataError{source, r.Line, detail, ErrDataFormat}
+		}
+		initLibItems(&items, row, r.Level)
+		// 直辖县级行政区划在各省同名, 不能建立索引, 只能通过编码访问.
+		if items[r.Code].kind == KindDirectlyAdministered {
+			continue
+		}
+		if err := initLibIndex(&index, &indexCache, row, r.Level); err != nil {
+			detail := fmt.Sprintf("%s, code = %s", err, r.Code)
+			return &DataError{source, r.Line, detail, ErrDataFormat}
+		}
+	}
+	cleanIndex(&index, &ambiguous)
+	shortNames := buildShortNames(items, nil)
+	indexShortNames(items, index, shortNames)
+
+	libItems, libIndex, libAmbiguous = items, index, ambiguous
+	libShortNames = shortNames
+	libNames, libNameSize = buildMatchNames(items, index, shortNames)
+	customMu.Lock()
+	libSets = make(map[RegionSet]map[string]bool)
+	libGroups = make(map[string]*groupScheme)
+	customMu.Unlock()
+	libGBCodes = make(map[string]string)
+	libTranslations = make(map[string]map[string]string)
+	return nil
+}
+
+// 规范化地址记录:
+// 1. 推断缺少的级别: 父节点为""或"0"的是省, 省的孩子是市, 市的孩子是区.
+// 2. 合并直辖市的"市辖区"和"县".
+// 3. 按省, 市, 区的顺序排列, 保证父节点先于子节点.
+func normalizeRecords(records []Record) ([]Record, error) {
+	byCode := make(map[string]*Record)
+	for i := range records {
+		r := &records[i]
+		if r.Parent == "0" {
+			r.Parent = ""
+		}
+		if _, ok := byCode[r.Code]; ok {
+			msg := fmt.Sprintf("duplicate code, code = %s", r.Code)
+			return nil, &DataError{"", r.Line, msg, ErrInconsistent}
+		}
+		byCode[r.Code] = r
+	}
+	levels := []Level{LevelProvince, LevelCity, LevelDistrict}
+	for i := range records {
+		r := &records[i]
+		// 层数超过3时停止, 防止父子关系存在环
+		depth := 0
+		for c := r.Parent; c != ""; c = byCode[c].Parent {
+			if _, ok := byCode[c]; !ok {
+				msg := fmt.Sprintf("parent code not found, code = %s, parent = %s", r.Code, c)
+				return nil, &DataError{"", r.Line, msg, ErrInconsistent}
+			}
+			depth++
+			if depth >= len(levels) {
+				msg := fmt.Sprintf("too many levels, code = %s", r.Code)
+				return nil, &DataError{"", r.Line, msg, ErrDataFormat}
+			}
+		}
+		if r.Level == "" {
+			r.Level = levels[depth]
+		}
+	}
+
+	records = mergeMunicipalityCities(records)
+	rank := map[Level]int{LevelProvince: 0, LevelCity: 1, LevelDistrict: 2}
+	sort.SliceStable(records, func(i, j int) bool {
+		return rank[records[i].Level] < rank[records[j].Level]
+	})
+	return records, nil
+}
+
+// 直辖市在很多数据集中有"市辖区"和"县"两个市级区划(例如: 重庆市 -> 市辖区, 县).
+// 把它们合并为第一个市, 并改名为省名. 其它市的孩子改为第一个市的孩子.
+func mergeMunicipalityCities(records []Record) []Record {
+	names := make(map[string]string) // 直辖市编码 -> 名称
+	for _, r := range records {
+		if r.Level == LevelProvince && kindOf(LevelProvince, r.Name, KindUnknown) == KindMunicipality {
+			names[r.Code] = r.Name
+		}
+	}
+	merged := make(map[string]string) // 被合并的市编码 -> 第一个市编码
+	first := make(map[string]string)  // 直辖市编码 -> 第一个市编码
+	result := make([]Record, 0, len(records))
+	for _, r := range records {
+		province, ok := names[r.Parent]
+		if ok && r.Level == LevelCity && (r.Name == "市辖区" || r.Name == "县" || r.Name == province) {
+			if code, ok := first[r.Parent]; ok {
+				merged[r.Code] = code
+				continue
+			}
+			first[r.Parent] = r.Code
+			r.Name = province
+		}
+		result = append(result, r)
+	}
+	for i := range result {
+		if code, ok := merged[result[i].Parent]; ok {
+			result[i].Parent = code
+		}
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package addlib
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// 数据库中的地址表. 零值表示默认的表结构, 与ExportSQL导出的表相同.
+type DBSource struct {
+	// 查询语句. 结果必须包括code, name和parent_code(或parent)列, level列可选, 其它列忽略.
+	// 若不为"", 则忽略下面的表名和列名.
+	Query string
+
+	Table        string // 表名, 默认为"divisions". 可以包括schema, 例如"master.divisions"
+	CodeColumn   string // 编码的列名, 默认为"code"
+	NameColumn   string // 名称的列名, 默认为"name"
+	ParentColumn string // 父节点编码的列名, 默认为"parent_code". 省的值为NULL, ""或"0"
+	LevelColumn  string // 级别的列名(可选). 值为province/city/district或1/2/3. 若为"", 则按父节点的层数推断
+}
+
+// 输出查询语句. 表名和列名原样拼接, 不能来自用户输入.
+func (s DBSource) query() string {
+	if s.Query != "" {
+		return s.Query
+	}
+	or := func(value string, defaultValue string) string {
+		if value == "" {
+			return defaultValue
+		}
+		return value
+	}
+	columns := []string{
+		or(s.CodeColumn, "code") + " AS code",
+		or(s.NameColumn, "name") + " AS name",
+		or(s.ParentColumn, "parent_code") + " AS parent_code",
+	}
+	if s.LevelColumn != "" {
+		columns = append(columns, s.LevelColumn+" AS level")
+	}
+	return "SELECT " + strings.Join(columns, ", ") + " FROM " + or(s.Table, "divisions")
+}
+
+// 从数据库初始化地址库. 校验规则与InitFile相同, 失败时保留原来的地址库.
+// db可以是任意database/sql驱动打开的数据库, 例如PostgreSQL.
+// 错误中的行号为结果集中的行号(从1开始).
+func InitDB(ctx context.Context, db *sql.DB, src DBSource) error {
+	query := src.query()
+	source := "database: " + query
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return &DataError{source, 0, "cannot query database", err}
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return &DataError{source, 0, "cannot read columns", err}
+	}
+	columns := map[string]int{"code": -1, "name": -1, "parent": -1, "level": -1}
+	for i, name := range names {
+		name = strings.ToLower(name)
+		if name == "parent_code" {
+			name = "parent"
+		}
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	for _, column := range []string{"code", "name", "parent"} {
+		if columns[column] < 0 {
+			detail := fmt.Sprintf("missing column, column = %s", column)
+			return &DataError{source, 0, detail, ErrDataFormat}
+		}
+	}
+
+	levels := map[string]Level{
+		"1": LevelProvince, "2": LevelCity, "3": LevelDistrict,
+		string(LevelProvince): LevelProvince, string(LevelCity): LevelCity, string(LevelDistrict): LevelDistrict,
+	}
+	records := make([]Record, 0)
+	values := make([]sql.NullString, len(names))
+	dest := make([]any, len(names))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for line := 1; rows.Next(); line++ {
+		if err := rows.Scan(dest...); err != nil {
+			return &DataError{source, line, err.Error(), ErrDataFormat}
+		}
+		field := func(column string) string {
+			if i := columns[column]; i >= 0 {
+				return strings.TrimSpace(values[i].String)
+			}
+			return ""
+		}
+		record := Record{"", field("parent"), field("code"), field("name"), line}
+		if value := field("level"); value != "" {
+			level, ok := levels[strings.ToLower(value)]
+			if !ok {
+				detail := fmt.Sprintf("unknown level, level = %s", value)
+				return &DataError{source, line, detail, ErrDataFormat}
+			}
+			record.Level = level
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return &DataError{source, 0, "cannot read rows", err}
+	}
+	return initRecords(source, records)
+}
@@ -0,0 +1,68 @@
+package addlib
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestInitDB(t *testing.T) {
+	defer Init("lib.add")
+	db := &fakeDB{
+		columns: []string{"code", "name", "parent_code", "level"},
+		rows: [][]driver.Value{
+			{"330100", "杭州市", "330000", "city"},
+			{"330000", "浙江省", nil, "province"},
+			{"330106", "西湖区", "330100", "district"},
+		},
+	}
+	if err := InitDB(context.Background(), openFakeDB(db), DBSource{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetCode("浙江", "杭州", "西湖"); got != "330106" {
+		t.Errorf("expected: 330106, got: %s", got)
+	}
+
+	tests := []struct {
+		src      DBSource
+		expected string
+	}{
+		{DBSource{}, "SELECT code AS code, name AS name, parent_code AS parent_code FROM divisions"},
+		{DBSource{Table: "master.region", CodeColumn: "id", ParentColumn: "pid", LevelColumn: "depth"},
+			"SELECT id AS code, name AS name, pid AS parent_code, depth AS level FROM master.region"},
+		{DBSource{Query: "SELECT * FROM v_region", Table: "foo"}, "SELECT * FROM v_region"},
+	}
+	for _, tt := range tests {
+		if got := tt.src.query(); got != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestInitDBErrors(t *testing.T) {
+	defer Init("lib.add")
+	tests := []struct {
+		db   *fakeDB
+		err  error
+		line int
+	}{
+		{&fakeDB{columns: []string{"code", "name"}}, ErrDataFormat, 0},
+		{&fakeDB{columns: []string{"code", "name", "parent", "level"}, rows: [][]driver.Value{
+			{"330000", "浙江省", "0", "1"}, {"330100", "杭州市", "330000", "9"}}}, ErrDataFormat, 2},
+		{&fakeDB{columns: []string{"code", "name", "parent"}, rows: [][]driver.Value{
+			{"330000", "浙江省", ""}, {"330106", "西湖区", "330100"}}}, ErrInconsistent, 2},
+		{&fakeDB{columns: []string{"code", "name", "parent"}, rows: [][]driver.Value{
+			{"330000", "浙江", ""}, {"320000", "浙江", ""}}}, ErrDataFormat, 2},
+	}
+	for _, tt := range tests {
+		err := InitDB(context.Background(), openFakeDB(tt.db), DBSource{})
+		var dataErr *DataError
+		if !errors.Is(err, tt.err) || !errors.As(err, &dataErr) || dataErr.Line != tt.line {
+			t.Errorf("expected: %v at line %d, got: %v", tt.err, tt.line, err)
+		}
+	}
+	if got := GetName("CN033001012"); got != "西湖区" {
+		t.Errorf("expected library unchanged after error, got: %s", got)
+	}
+}
@@ -0,0 +1,206 @@
+package addlib
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSON和YAML的嵌套格式: 省 -> 市 -> 区.
+// 编码的字段名为code或value, 名称的字段名为name或label.
+// 例: [{"code": "330000", "name": "浙江省", "children": [{"code": "330100", "name": "杭州市"}]}]
+type treeNode struct {
+	Code     jsonString `json:"code"`
+	Value    jsonString `json:"value"`
+	Name     string     `json:"name"`
+	Label    string     `json:"label"`
+	Children []treeNode `json:"children"`
+}
+
+// 兼容字符串和数字的JSON字段, 例如: "330000"和330000
+type jsonString string
+
+func (s *jsonString) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = jsonString(str)
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return err
+	}
+	*s = jsonString(num.String())
+	return nil
+}
+
+// 把嵌套的节点展开为地址记录
+func flattenTree(nodes []treeNode, parent string, records *[]Record) {
+	for _, node := range nodes {
+		code, name := string(node.Code), node.Name
+		if code == "" {
+			code = string(node.Value)
+		}
+		if name == "" {
+			name = node.Label
+		}
+		*records = append(*records, Record{"", parent, code, name, 0})
+		flattenTree(node.Children, code, records)
+	}
+}
+
+// 读取嵌套的JSON数据
+func loadJSON(r io.Reader) ([]Record, error) {
+	nodes := make([]treeNode, 0)
+	if err := json.NewDecoder(r).Decode(&nodes); err != nil {
+		return nil, &DataError{"", 0, err.Error(), ErrDataFormat}
+	}
+	records := make([]Record, 0)
+	flattenTree(nodes, "", &records)
+	return records, nil
+}
+
+// 读取扁平的CSV数据.
+// 第1行为表头, 必须包括code, name和parent(或parent_code)列, 其它列忽略.
+// 省的parent为空或0.
+func loadCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, &DataError{"", 1, "cannot read header", ErrDataFormat}
+	}
+	columns := map[string]int{"code": -1, "name": -1, "parent": -1}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if column == "parent_code" {
+			column = "parent"
+		}
+		if _, ok := columns[column]; ok {
+			columns[column] = i
+		}
+	}
+	for column, i := range columns {
+		if i < 0 {
+			detail := fmt.Sprintf("missing column, column = %s", column)
+			return nil, &DataError{"", 1, detail, ErrDataFormat}
+		}
+	}
+
+	records := make([]Record, 0)
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &DataError{"", line, err.Error(), ErrDataFormat}
+		}
+		field := func(column string) string {
+			if i := columns[column]; i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		records = append(records, Record{"", field("parent"), field("code"), field("name"), line})
+	}
+	return records, nil
+}
+
+// 读取嵌套的YAML数据.
+// 仅支持与JSON相同结构的列表(每个列表项包括code, name和children), 不支持YAML的其它语法(例如锚点, 多行字符串和流式写法).
+// 例子参考README"其它数据格式".
+func loadYAML(r io.Reader) ([]Record, error) {
+	type frame struct {
+		indent int // 列表项"-"的缩进
+		record int // 列表项对应的记录
+	}
+	records := make([]Record, 0)
+	parents := make([]int, 0) // 每个记录的父节点记录, 省为-1
+	stack := make([]frame, 0)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := stripYAMLComment(scanner.Text())
+		content := strings.TrimSpace(text)
+		if content == "" || strings.HasPrefix(content, "#") || content == "---" {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+
+		// 弹出缩进不小于当前行的列表项. 对于新的列表项, 栈顶即为父节点; 对于键值, 栈顶即为所属的列表项.
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if strings.HasPrefix(content, "- ") || content == "-" {
+			parent := -1
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1].record
+			}
+			records = append(records, Record{"", "", "", "", line})
+			parents = append(parents, parent)
+			stack = append(stack, frame{indent, len(records) - 1})
+			content = strings.TrimSpace(strings.TrimPrefix(content, "-"))
+			if content == "" {
+				continue
+			}
+		}
+		if len(stack) == 0 {
+			return nil, &DataError{"", line, "expected a list item", ErrDataFormat}
+		}
+
+		key, value, ok := strings.Cut(content, ":")
+		if !ok {
+			detail := fmt.Sprintf("expected key: value, text = %s", content)
+			return nil, &DataError{"", line, detail, ErrDataFormat}
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		}
+		current := &records[stack[len(stack)-1].record]
+		switch strings.TrimSpace(key) {
+		case "code", "value":
+			current.Code = value
+		case "name", "label":
+			current.Name = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &DataError{"", 0, err.Error(), ErrDataFormat}
+	}
+	for i, parent := range parents {
+		if parent >= 0 {
+			records[i].Parent = records[parent].Code
+		}
+	}
+	return records, nil
+}
+
+// 去掉YAML行尾的注释: 行首或空白字符之后的"#", 引号中的除外.
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote == '"' && c == '\\':
+			i++ // 跳过转义的字符
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
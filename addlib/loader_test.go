@@ -0,0 +1,140 @@
+package addlib
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInitFileJSON(t *testing.T) {
+	defer Init("lib.add")
+	dataPath := writeTestData(t, map[string]string{"pca.json": `[
+		{"code": 500000, "name": "重庆市", "children": [
+			{"code": "500100", "name": "市辖区", "children": [{"code": "500103", "name": "渝中区"}]},
+			{"code": "500200", "name": "县", "children": [{"code": "500229", "name": "城口县"}]}
+		]},
+		{"value": "420000", "label": "湖北省", "children": [
+			{"value": "420100", "label": "武汉市", "children": [{"value": "420106", "label": "武昌区"}]},
+			{"value": "429000", "label": "省直辖县级行政区划"}
+		]},
+		{"code": "410000", "name": "河南省", "children": [{"code": "419000", "name": "省直辖县级行政区划"}]}
+	]`})
+	if err := InitFile(dataPath+"/pca.json", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Cities("重庆"); len(got) != 1 || got[0] != "重庆市" {
+		t.Errorf("expected: [重庆市], got: %s", got)
+	}
+	if got := len(Districts("重庆")); got != 2 {
+		t.Errorf("expected: 2, got: %d", got)
+	}
+	if got := GetCode("重庆", "", "城口"); got != "500229" {
+		t.Errorf("expected: 500229, got: %s", got)
+	}
+	if got := GetCode("", "武汉", "武昌"); got != "420106" {
+		t.Errorf("expected: 420106, got: %s", got)
+	}
+	if got := GetKind("429000"); got != KindDirectlyAdministered {
+		t.Errorf("expected: %s, got: %s", KindDirectlyAdministered, got)
+	}
+}
+
+func TestInitFileCSV(t *testing.T) {
+	defer Init("lib.add")
+	dataPath := writeTestData(t, map[string]string{"divisions.csv": "\ufeffcode,name,parent_code,level\n" +
+		"330106,西湖区,330100,3\n" +
+		"330000,浙江省,0,1\n" +
+		"330100,杭州市,330000,2\n"})
+	if err := InitFile(dataPath+"/divisions.csv", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ParseCode("330106"); got != (AddressCodes{"330000", "330100", "330106"}) {
+		t.Errorf("unexpected codes: %v", got)
+	}
+
+	dataPath = writeTestData(t, map[string]string{"orphan.csv": "code,name,parent\n330000,浙江省,\n330106,西湖区,330100\n"})
+	err := InitFile(dataPath+"/orphan.csv", "")
+	var dataErr *DataError
+	if !errors.Is(err, ErrInconsistent) || !errors.As(err, &dataErr) || dataErr.Line != 3 {
+		t.Errorf("expected inconsistent data at line 3, got: %v", err)
+	}
+	if got := GetName("330106"); got != "西湖区" {
+		t.Errorf("expected library unchanged after error, got: %s", got)
+	}
+}
+
+func TestInitFileYAML(t *testing.T) {
+	defer Init("lib.add")
+	dataPath := writeTestData(t, map[string]string{"divisions.yml": `# 行政区划
+- code: "330000"
+  name: 浙江省
+  children:
+    - code: "330100"
+      name: 杭州市 # 省会
+      children:
+      - name: 西湖区
+        code: '330106'
+    - code: "330200"
+      name: 宁波市
+- code: "320000"
+  children:
+    - code: "320100"
+      name: 南京市
+  name: 江苏省
+`})
+	if err := InitFile(dataPath+"/divisions.yml", ""); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{GetCode("", "杭州", "西湖"), "330106"},
+		{GetCode("", "宁波", ""), "330200"},
+		{GetCode("江苏", "", ""), "320000"},
+		{Parent("320100"), "320000"},
+	}
+	for _, tt := range tests {
+		if tt.in != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, tt.in)
+		}
+	}
+}
+
+func TestLoadYAMLComments(t *testing.T) {
+	records, err := loadYAML(strings.NewReader(`- code: "1" # 注释
+  name: "A #1" # 引号中的"#"不是注释
+- code: '2'
+  name: 'B ''#2'''
+- code: 3#
+  name: "C \" #3"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Record{{"", "", "1", "A #1", 1}, {"", "", "2", "B '#2'", 3}, {"", "", "3#", `C " #3`, 5}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected: %v, got: %v", expected, records)
+	}
+}
+
+func TestRegisterLoader(t *testing.T) {
+	defer Init("lib.add")
+	defer delete(loaders, "test")
+	RegisterLoader("test", LoaderFunc(func(r io.Reader) ([]Record, error) {
+		return []Record{{LevelProvince, "", "P1", "浙江省", 0}}, nil
+	}))
+	dataPath := writeTestData(t, map[string]string{"data.txt": ""})
+	if err := InitFile(dataPath+"/data.txt", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if got := Provinces(false); len(got) != 1 || got[0] != "浙江省" {
+		t.Errorf("expected: [浙江省], got: %s", got)
+	}
+	if err := InitFile(dataPath+"/data.txt", ""); !errors.Is(err, ErrDataFormat) {
+		t.Errorf("expected: %v, got: %v", ErrDataFormat, err)
+	}
+}
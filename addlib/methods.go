@@ -1,14 +1,11 @@
 package addlib
 
-import (
-	"errors"
-	"fmt"
-)
-
 type Address struct {
 	Province string
 	City     string
 	District string
+	// 是否为直辖市(北京, 上海, 天津, 重庆). 直辖市的省名和市名相同.
+	Municipality bool
 }
 
 type AddressCodes struct {
@@ -23,64 +20,72 @@ type AddressCodes struct {
 // --------------
 
 // 输出所有省编码
+// 若mainland为true, 则剔除港澳台, 等价于ProvinceCodesIn(SetMainland).
+// 若数据中没有定义SetMainland, 则按省名剔除香港, 澳门和台湾.
 func ProvinceCodes(mainland bool) []string {
-	islands := [3]string{"CN002000000", "CN027000000", "CN029000000"}
-	fullResult := libItems[ROOT].children
-	//剔除港澳台
-	noIslands := make([]string, len(fullResult) - 3)
-	isIsland := false
-	if mainland == true {
-		k := 0
-		for _, v := range fullResult {
-			for i := 0; i < len(islands); i++ {
-				if v == islands[i] {
-					isIsland = true
-					break
-				}
-			}
-			if isIsland {
-				isIsland = false
-				continue
-			}
-			noIslands[k] = v
-			k++
-		}
-		return noIslands
-	} else {
-		return fullResult
+	if mainland {
+		return ProvinceCodesIn(SetMainland)
 	}
+	return libItems[ROOT].children
 }
 
 // 输入省编码, 输出它所管辖的市编码
+// 可选输入类型(kinds), 仅输出指定类型的市编码. 例如: CityCodes(code, KindAutonomousPrefecture)
 // 若输入错误, 则返回空[]
-func CityCodes(ofProvinceCode string) []string {
+func CityCodes(ofProvinceCode string, kinds ...Kind) []string {
 	if p, ok := libItems[ofProvinceCode]; ok {
-		return p.children
+		return filterByKind(p.children, kinds)
 	}
 	return make([]string, 0)
 }
 
 // 输入市编码, 输出它所管辖的区编码
+// 可选输入类型(kinds), 仅输出指定类型的区编码. 例如: DistrictCodes(code, KindCounty, KindAutonomousCounty)
 // 若输入错误, 则返回空[]
-func DistrictCodes(ofCityCode string) []string {
+func DistrictCodes(ofCityCode string, kinds ...Kind) []string {
 	if p, ok := libItems[ofCityCode]; ok {
-		return p.children
+		return filterByKind(p.children, kinds)
 	}
 	return make([]string, 0)
 }
 
-// 输入编码, 输出其标准地址名称
+// 输入编码, 输出其统计区划级别
 // 若输入错误, 则返回""
-func GetName(code string) string {
+func GetLevel(code string) Level {
 	if item, ok := libItems[code]; ok {
-		return item.name
+		return item.level
 	}
 	return ""
 }
 
+// 输入编码, 输出其行政区划类型
+// 若输入错误, 则返回KindUnknown
+func GetKind(code string) Kind {
+	if item, ok := libItems[code]; ok {
+		return item.kind
+	}
+	return KindUnknown
+}
+
+// 输入编码, 输出其标准地址名称
+// 若输入错误, 则返回""
+func GetName(code string) string {
+	name, _ := LookupName(code)
+	return name
+}
+
+// 输入编码, 输出其标准地址名称
+// 若编码不存在, 则返回ErrNotFound
+func LookupName(code string) (string, error) {
+	if item, ok := libItems[code]; ok && code != ROOT {
+		return item.name, nil
+	}
+	return "", lookupError("", code, ErrNotFound)
+}
+
 // 输入地址名称, 输出对应的编码
 // 说明:
-// 1. 查询区名时, 必须指定市名
+// 1. 查询区名时, 必须指定市名. 直辖市除外: 若省名是直辖市, 则可以不指定市名
 // 2. 查询市名时, 可以不指定省名
 // 3. 如果省市区的名字全部指定, 则按照区->市->省的顺序查找, 并返回第一个有效的编码
 // 4. 要求输入的汉字至少是2个, 而且前两个汉字和标准名称的前2个汉字相同(相同的汉字个数越多, 查询到正确编码的机会越大)
@@ -90,6 +95,9 @@ func GetCode(provinceName string, cityName string, districtName string) string {
 	if provinceName != "" {
 		provinceCode = GetProvinceCode(provinceName)
 	}
+	if cityName == "" && districtName != "" && isMunicipality(provinceCode) {
+		cityName = provinceName
+	}
 	if cityName != "" {
 		cityCode = GetCityCode(cityName)
 		if districtName != "" {
@@ -108,42 +116,103 @@ func GetCode(provinceName string, cityName string, districtName string) string {
 	return ""
 }
 
+// 输入地址名称, 输出对应的编码
+// 与GetCode不同, 所有非空的名称都必须有效, 而且省市区之间必须存在管辖关系.
+// 错误:
+// 1. ErrNotFound - 名称不存在, 或者查询区名时没有指定市名
+// 2. ErrAmbiguous - 名称对应多个编码, 需要输入更多的汉字
+// 3. ErrInconsistent - 市不属于输入的省
+func LookupCode(provinceName string, cityName string, districtName string) (string, error) {
+
+	provinceCode, cityCode := "", ""
+	var err error
+	if provinceName != "" {
+		if provinceCode, err = LookupProvinceCode(provinceName); err != nil {
+			return "", err
+		}
+	}
+	if cityName == "" && districtName != "" && isMunicipality(provinceCode) {
+		cityName = provinceName
+	}
+	if cityName != "" {
+		if cityCode, err = LookupCityCode(cityName); err != nil {
+			return "", err
+		}
+		if provinceCode != "" && libItems[cityCode].parent != provinceCode {
+			return "", lookupError(LevelCity, cityName, ErrInconsistent)
+		}
+	}
+	if districtName != "" {
+		if cityCode == "" {
+			return "", lookupError(LevelCity, cityName, ErrNotFound)
+		}
+		return lookupIndex(cityCode, LevelDistrict, districtName)
+	}
+	if cityCode != "" {
+		return cityCode, nil
+	}
+	if provinceCode != "" {
+		return provinceCode, nil
+	}
+	return "", lookupError("", "", ErrNotFound)
+}
+
 // 输入省名, 输出省编码
 func GetProvinceCode(provinceName string) string {
-	key, _ := formatKey(levelProvince, provinceName, 2)
-	if code, ok := libIndex[key]; ok {
-		return code
-	}
-	return ""
+	code, _ := LookupProvinceCode(provinceName)
+	return code
+}
+
+// 输入省名, 输出省编码
+// 若省名不存在, 则返回ErrNotFound
+func LookupProvinceCode(provinceName string) (string, error) {
+	return lookupIndex(string(LevelProvince), LevelProvince, provinceName)
 }
 
 // 输入市名, 输出市编码
 func GetCityCode(cityName string) string {
-	minKeySize, maxKeySize := 2, len([]rune(cityName))
-	for keySize := minKeySize; keySize <= maxKeySize; keySize++ {
-		key, _ := formatKey(levelCity, cityName, keySize)
-		if code, ok := libIndex[key]; ok {
-			return code
-		}
-	}
-	return ""
+	code, _ := LookupCityCode(cityName)
+	return code
+}
+
+// 输入市名, 输出市编码
+// 若市名不存在, 则返回ErrNotFound. 若市名对应多个市, 则返回ErrAmbiguous
+func LookupCityCode(cityName string) (string, error) {
+	return lookupIndex(string(LevelCity), LevelCity, cityName)
 }
 
 // 输入市名和区名, 输出区编码
 func GetDistrictCode(cityName string, districtName string) string {
+	code, _ := LookupDistrictCode(cityName, districtName)
+	return code
+}
 
-	maxKeySize := len([]rune(districtName))
-	cityCode := GetCityCode(cityName)
-	if cityCode == "" {
-		return ""
+// 输入市名和区名, 输出区编码
+// 若市名或区名不存在, 则返回ErrNotFound. 若市名或区名对应多个编码, 则返回ErrAmbiguous
+func LookupDistrictCode(cityName string, districtName string) (string, error) {
+	cityCode, err := LookupCityCode(cityName)
+	if err != nil {
+		return "", err
 	}
-	for keySize := 2; keySize <= maxKeySize; keySize++ {
-		key, _ := formatKey(cityCode, districtName, keySize)
+	return lookupIndex(cityCode, LevelDistrict, districtName)
+}
+
+// 输入名称, 按前k个汉字(k = 2, 3, ...)依次查询中文索引, 输出第一个有效的编码.
+// 输入: prefix - 索引的前缀, level - 级别(用于错误信息), name - 地址名称
+// 若完整的名称对应有歧义的索引, 则返回ErrAmbiguous, 否则返回ErrNotFound.
+func lookupIndex(prefix string, level Level, name string) (string, error) {
+	ambiguous := false
+	for keySize := 2; keySize <= len([]rune(name)); keySize++ {
+		key, _ := formatKey(prefix, name, keySize)
 		if code, ok := libIndex[key]; ok {
-			return code
+			return code, nil
 		}
+		ambiguous = libAmbiguous[key]
 	}
-	return ""
+	if ambiguous {
+		return "", lookupError(level, name, ErrAmbiguous)
+	}
+	return "", lookupError(level, name, ErrNotFound)
 }
 
 // 输入地址编码, 输出其所属省市区编码.
@@ -167,8 +236,7 @@ func ParseCode(code string) (AddressCodes, error) {
 		addc.CityCode = parsedCodes[1]
 		addc.DistrictCode = parsedCodes[0]
 	default:
-		msg := fmt.Sprintf("invalid code, code = %s", code)
-		return addc, errors.New(msg)
+		return addc, lookupError("", code, ErrNotFound)
 	}
 	return addc, nil
 }
@@ -193,69 +261,110 @@ func autoParseCodes(code string, ptParsedCodes *[]string) {
 
 // 输入省市区名称, 解析其标准三级地址名称
 // 例: ParseAddress("", "杭州", "西湖") -> {浙江省 杭州市 西湖区}
+// 直辖市的市名与省名相同, 可以只指定省名和区名.
+// 例: ParseAddress("重庆", "", "渝中") -> {重庆市 重庆市 渝中区}
 func ParseAddress(provinceName string, cityName string, districtName string) (Address, error) {
 
-	add := Address{"", "", ""}
+	add := Address{"", "", "", false}
+	if cityName == "" && districtName != "" && isMunicipality(GetProvinceCode(provinceName)) {
+		cityName = provinceName
+	}
+	// 记录最具体的级别的查询错误
+	var firstErr error
 	if districtName != "" && cityName != "" {
-		if code := GetDistrictCode(cityName, districtName); code != "" {
+		code, err := LookupDistrictCode(cityName, districtName)
+		if err == nil {
 			add.District = GetName(code)
 			cityCode := libItems[code].parent
 			add.City = GetName(cityCode)
 			add.Province = GetName(libItems[cityCode].parent)
+			add.Municipality = isMunicipality(libItems[cityCode].parent)
 			return add, nil
 		}
+		firstErr = err
 	}
 
 	if cityName != "" {
-		if code := GetCityCode(cityName); code != "" {
+		code, err := LookupCityCode(cityName)
+		if err == nil {
 			add.City = GetName(code)
 			add.Province = GetName(libItems[code].parent)
+			add.Municipality = isMunicipality(libItems[code].parent)
 			return add, nil
 		}
+		if firstErr == nil {
+			firstErr = err
+		}
 	}
 
 	if provinceName != "" {
-		if code := GetProvinceCode(provinceName); code != "" {
+		code, err := LookupProvinceCode(provinceName)
+		if err == nil {
 			add.Province = GetName(code)
+			add.Municipality = isMunicipality(code)
 			return add, nil
 		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr == nil {
+		firstErr = lookupError("", "", ErrNotFound)
+	}
+	return add, firstErr
+}
+
+// 输出完整的地址名称
+// 直辖市的省名和市名相同, 只保留一个. 例如: {北京市 北京市 东城区} -> 北京市东城区
+func (add Address) String() string {
+	if add.Municipality && add.City == add.Province {
+		return add.Province + add.District
 	}
+	return add.Province + add.City + add.District
+}
 
-	msg := fmt.Sprintf("address not found, provinceName = %s, cityName = %s, districtName = %s",
-		provinceName, cityName, districtName)
-	return add, errors.New(msg)
+// 判断省编码是否为直辖市
+func isMunicipality(provinceCode string) bool {
+	if item, ok := libItems[provinceCode]; ok {
+		return item.level == LevelProvince && item.kind == KindMunicipality
+	}
+	return false
 }
 
 // 输出省名列表
-func Provinces(mainland bool) []string {
+// 可选输入区域集合(sets), 仅输出属于所有集合的省名. 例如: Provinces(false, SetHMT)
+func Provinces(mainland bool, sets ...RegionSet) []string {
 	provinces := make([]string, 0)
-	for _, code := range ProvinceCodes(mainland) {
+	for _, code := range filterByRegionSets(ProvinceCodes(mainland), sets) {
 		provinces = append(provinces, GetName(code))
 	}
 	return provinces
 }
 
 // 输入省名, 输出它管辖的所有市名
-func Cities(ofProvince string) []string {
+// 可选输入区域集合(sets), 仅输出属于所有集合的市名.
+func Cities(ofProvince string, sets ...RegionSet) []string {
 	cities := make([]string, 0)
 	provinceCode := GetProvinceCode(ofProvince)
 	if provinceCode == "" {
 		return cities
 	}
-	for _, code := range CityCodes(provinceCode) {
+	for _, code := range filterByRegionSets(CityCodes(provinceCode), sets) {
 		cities = append(cities, GetName(code))
 	}
 	return cities
 }
 
 // 输入市名, 输出它管辖的所有区名
-func Districts(ofCity string) []string {
+// 可选输入区域集合(sets), 仅输出属于所有集合的区名.
+func Districts(ofCity string, sets ...RegionSet) []string {
 	districts := make([]string, 0)
 	cityCode := GetCityCode(ofCity)
 	if cityCode == "" {
 		return districts
 	}
-	for _, code := range DistrictCodes(cityCode) {
+	for _, code := range filterByRegionSets(DistrictCodes(cityCode), sets) {
 		districts = append(districts, GetName(code))
 	}
 	return districts
@@ -94,6 +94,8 @@ func TestGetCode(t *testing.T) {
 		{"", "巴彦淖尔市", "乌拉特后旗", "CN019003004"},
 		{"", "巴彦淖尔市", "乌拉特中旗", "CN019003006"},
 		{"", "巴彦淖尔市", "乌拉特前旗", "CN019003005"},
+		{"重庆", "", "渝中", "CN034002023"},
+		{"北京", "北京", "", "CN003001000"},
 		{"foo", "bar", "", ""},
 		{"啊", "", "", ""},
 		{"", "哈", "", ""},
@@ -192,22 +194,46 @@ func TestParseAddress(t *testing.T) {
 		inDistrict string
 		expected   Address
 	}{
-		{"浙江", "杭州", "西湖", Address{"浙江省", "杭州市", "西湖区"}},
-		{"", "杭州", "西湖", Address{"浙江省", "杭州市", "西湖区"}},
-		{"", "杭州", "", Address{"浙江省", "杭州市", ""}},
-		{"浙江", "", "", Address{"浙江省", "", ""}},
-		{"foo", "bar", "", Address{"", "", ""}},
+		{"浙江", "杭州", "西湖", Address{"浙江省", "杭州市", "西湖区", false}},
+		{"", "杭州", "西湖", Address{"浙江省", "杭州市", "西湖区", false}},
+		{"", "杭州", "", Address{"浙江省", "杭州市", "", false}},
+		{"浙江", "", "", Address{"浙江省", "", "", false}},
+		{"", "重庆", "渝中", Address{"重庆市", "重庆市", "渝中区", true}},
+		{"重庆", "", "渝中", Address{"重庆市", "重庆市", "渝中区", true}},
+		{"北京", "", "", Address{"北京市", "", "", true}},
+		{"foo", "bar", "", Address{"", "", "", false}},
 	}
 	for _, tt := range tests {
 		got, _ := ParseAddress(tt.inProvince, tt.inCity, tt.inDistrict)
-		strGot := fmt.Sprintf("%s-%s-%s", got.Province, got.City, got.District)
-		strExpected := fmt.Sprintf("%s-%s-%s", tt.expected.Province, tt.expected.City, tt.expected.District)
+		strGot := fmt.Sprintf("%s-%s-%s-%t", got.Province, got.City, got.District, got.Municipality)
+		strExpected := fmt.Sprintf("%s-%s-%s-%t", tt.expected.Province, tt.expected.City,
+			tt.expected.District, tt.expected.Municipality)
 		if strGot != strExpected {
 			t.Errorf("expected: %s, got: %s", strExpected, strGot)
 		}
 	}
 }
 
+func TestAddressString(t *testing.T) {
+	tests := []struct {
+		inProvince string
+		inCity     string
+		inDistrict string
+		expected   string
+	}{
+		{"浙江", "杭州", "西湖", "浙江省杭州市西湖区"},
+		{"", "北京", "东城", "北京市东城区"},
+		{"上海", "", "", "上海市"},
+		{"", "上海", "", "上海市"},
+	}
+	for _, tt := range tests {
+		got, _ := ParseAddress(tt.inProvince, tt.inCity, tt.inDistrict)
+		if got.String() != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got.String())
+		}
+	}
+}
+
 func TestProvinces(t *testing.T) {
 	expected := 34
 	expectedMainland := 31
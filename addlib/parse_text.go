@@ -0,0 +1,213 @@
+package addlib
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 解析自由文本中的地址, 输出标准三级地址和剩余的详细地址.
+// 例: ParseText("浙江省杭州市西湖区文三路100号") -> {浙江省 杭州市 西湖区}, "文三路100号"
+// 规则如下:
+// 1. 从文本开头依次匹配省, 市, 区的名称, 可以是标准名称, 简称(参考Region.ShortName)或中文索引的前k个汉字.
+// 2. 每一级都可以省略, 例如"杭州西湖文三路"或"西湖区文三路". 省略的上级由下级推断.
+// 3. 直辖市可以省略市名, 例如"北京市朝阳区".
+// 4. 只匹配到上级时也算成功, 例如"浙江省某某路" -> {浙江省 "" ""}.
+// 若一个名称都没有匹配到, 则返回ErrNotFound; 若同样长的名称对应多个区划(例如: "西湖区"), 则返回ErrAmbiguous.
+func ParseText(text string) (Address, string, error) {
+	add, _, detail, err := parseText(text)
+	return add, detail, err
+}
+
+// 与ParseText相同, 同时输出匹配到的最具体的一级的编码.
+// 编码直接取自匹配结果, 不能由标准名称反查: 直辖县级行政区划(例如: 仙桃市)的上级在各省同名, 没有索引.
+func parseText(text string) (Address, string, string, error) {
+	rest := trimSeparators(text)
+	rest = trimSeparators(strings.TrimPrefix(rest, "中国"))
+
+	// 省. 若从开头能匹配到更长的市名(例如: "吉林市"), 则跳过省.
+	provinceCode, provinceRest, err := matchName(rest, LevelProvince, ROOT)
+	if err != nil {
+		return Address{}, "", text, err
+	}
+	if provinceCode != "" {
+		cityCode, cityRest, _ := matchName(rest, LevelCity, ROOT)
+		if cityCode != "" && len(cityRest) < len(provinceRest) {
+			provinceCode = ""
+		} else {
+			rest = trimSeparators(provinceRest)
+		}
+	}
+
+	// 市
+	within := ROOT
+	if provinceCode != "" {
+		within = provinceCode
+	}
+	cityCode, cityRest, err := matchName(rest, LevelCity, within)
+	if err != nil {
+		return Address{}, "", text, err
+	}
+	rest = trimSeparators(cityRest)
+	if cities := libItems[within].children; cityCode == "" && isMunicipality(provinceCode) && len(cities) == 1 {
+		cityCode = cities[0]
+	}
+
+	// 区
+	if cityCode != "" {
+		within = cityCode
+	}
+	districtCode, districtRest, err := matchName(rest, LevelDistrict, within)
+	if err != nil {
+		return Address{}, "", text, err
+	}
+	rest = districtRest
+
+	// 由下级推断上级
+	if districtCode != "" {
+		cityCode = libItems[districtCode].parent
+	}
+	if cityCode != "" {
+		provinceCode = libItems[cityCode].parent
+	}
+	if provinceCode == "" {
+		return Address{}, "", text, lookupError("", text, ErrNotFound)
+	}
+	add := Address{GetName(provinceCode), GetName(cityCode), GetName(districtCode), isMunicipality(provinceCode)}
+	code := provinceCode
+	if districtCode != "" {
+		code = districtCode
+	} else if cityCode != "" {
+		code = cityCode
+	}
+	return add, code, trimSeparators(rest), nil
+}
+
+// 查找名称出现在text开头的区划, 输出编码和text剩余的部分.
+// 只查找级别为level, 且在within之下(within为ROOT时不限制)的区划.
+// 若匹配到多个, 则取最长的名称; 若同样长的名称对应多个区划, 则返回ErrAmbiguous.
+// 若没有匹配到, 则返回""和原来的text.
+func matchName(text string, level Level, within string) (string, string, error) {
+	// text开头的每个前缀的字节数(最多libNameSize个汉字), 由短到长
+	sizes := make([]int, 0, libNameSize)
+	for i := range text {
+		if i > 0 {
+			sizes = append(sizes, i)
+		}
+		if len(sizes) == libNameSize {
+			break
+		}
+	}
+	if len(sizes) < libNameSize {
+		sizes = append(sizes, len(text))
+	}
+	for i := len(sizes) - 1; i >= 0; i-- {
+		matched, ambiguous := "", false
+		for _, code := range libNames[text[:sizes[i]]] {
+			if libItems[code].level != level || !isDescendant(code, within) {
+				continue
+			}
+			if matched != "" && matched != code {
+				ambiguous = true
+			}
+			matched = code
+		}
+		if ambiguous {
+			return "", text, lookupError(level, text[:sizes[i]], ErrAmbiguous)
+		}
+		if matched != "" {
+			return matched, text[sizes[i]:], nil
+		}
+	}
+	return "", text, nil
+}
+
+// 判断code是否为ancestor的后代. 所有区划都是ROOT的后代.
+func isDescendant(code string, ancestor string) bool {
+	for c := libItems[code].parent; c != ""; c = libItems[c].parent {
+		if c == ancestor {
+			return true
+		}
+		if c == ROOT {
+			return false
+		}
+	}
+	return false
+}
+
+// 自由文本可以匹配的名称: 名称 -> 编码列表.
+// 名称包括标准名称, 简称和中文索引中的前k个汉字. 每次加载地址库时重新生成(参考buildMatchNames).
+var libNames = make(map[string][]string)
+
+// libNames中最长的名称的汉字个数
+var libNameSize = 0
+
+// 生成所有区划可以匹配的名称, 以及最长的名称的汉字个数
+func buildMatchNames(items map[string]*libItem, index map[string]string, shortNames map[string]string) (map[string][]string, int) {
+	names := make(map[string][]string)
+	size := 0
+	add := func(name string, code string) {
+		for _, c := range names[name] {
+			if c == code {
+				return
+			}
+		}
+		names[name] = append(names[name], code)
+		if n := len([]rune(name)); n > size {
+			size = n
+		}
+	}
+	for code, item := range items {
+		if code == ROOT {
+			continue
+		}
+		add(item.name, code)
+		add(shortNames[code], code)
+		prefix := string(item.level)
+		if item.level == LevelDistrict {
+			prefix = item.parent
+		}
+		runes := []rune(item.name)
+		for keySize := 2; keySize < len(runes); keySize++ {
+			key, _ := formatKey(prefix, item.name, keySize)
+			if index[key] == code {
+				add(string(runes[:keySize]), code)
+				break
+			}
+		}
+	}
+	return names, size
+}
+
+// 去掉开头的空白字符和标点符号, 例如: "浙江省, 杭州市" 中的", "
+func trimSeparators(text string) string {
+	return strings.TrimLeftFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+}
+
+// 地址的解析结果
+type Resolution struct {
+	Address
+	Code   string // 最具体的一级的编码
+	Detail string // 自由文本中剩余的详细地址
+}
+
+// 解析一条地址: 若省市区名称都为空, 则按自由文本解析text(参考ParseText), 否则按LookupCode的规则查询.
+// 例: Resolve("", "杭州", "西湖", "") -> {{浙江省 杭州市 西湖区 false} CN033001012 ""}
+func Resolve(provinceName string, cityName string, districtName string, text string) (Resolution, error) {
+	if provinceName == "" && cityName == "" && districtName == "" {
+		add, code, detail, err := parseText(text)
+		if err != nil {
+			return Resolution{}, err
+		}
+		return Resolution{add, code, detail}, nil
+	}
+	code, err := LookupCode(provinceName, cityName, districtName)
+	if err != nil {
+		return Resolution{}, err
+	}
+	codes, _ := ParseCode(code)
+	add := Address{GetName(codes.ProvinceCode), GetName(codes.CityCode), GetName(codes.DistrictCode),
+		isMunicipality(codes.ProvinceCode)}
+	return Resolution{add, code, ""}, nil
+}
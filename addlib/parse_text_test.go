@@ -0,0 +1,88 @@
+package addlib
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseText(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Address
+		detail   string
+		err      error
+	}{
+		{"浙江省杭州市西湖区文三路100号", Address{"浙江省", "杭州市", "西湖区", false}, "文三路100号", nil},
+		{"中国 浙江, 杭州, 西湖 文三路", Address{"浙江省", "杭州市", "西湖区", false}, "文三路", nil},
+		{"杭州西湖文三路", Address{"浙江省", "杭州市", "西湖区", false}, "文三路", nil},
+		{"北京市朝阳区建国路", Address{"北京市", "北京市", "朝阳区", true}, "建国路", nil},
+		{"上海浦东新区世纪大道", Address{"上海市", "上海市", "浦东新区", true}, "世纪大道", nil},
+		{"广西南宁市", Address{"广西壮族自治区", "南宁市", "", false}, "", nil},
+		{"吉林市船营区", Address{"吉林省", "吉林市", "船营区", false}, "", nil},
+		{"浙江省某某路", Address{"浙江省", "", "", false}, "某某路", nil},
+		{"西湖区文三路", Address{}, "西湖区文三路", ErrAmbiguous},
+		{"某某路", Address{}, "某某路", ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		got, detail, err := ParseText(tt.in)
+		if got != tt.expected || detail != tt.detail || !errors.Is(err, tt.err) {
+			t.Errorf("expected: %s, %s, %v, got: %s, %s, %v", tt.expected, tt.detail, tt.err, got, detail, err)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		in       [4]string
+		expected Resolution
+		err      error
+	}{
+		{[4]string{"", "杭州", "西湖", ""}, Resolution{Address{"浙江省", "杭州市", "西湖区", false}, "CN033001012", ""}, nil},
+		{[4]string{"重庆", "", "", "忽略"}, Resolution{Address{"重庆市", "", "", true}, "CN034000000", ""}, nil},
+		{[4]string{"", "", "", "杭州西湖文三路"}, Resolution{Address{"浙江省", "杭州市", "西湖区", false}, "CN033001012", "文三路"}, nil},
+		{[4]string{"江苏", "杭州", "", ""}, Resolution{}, ErrInconsistent},
+		{[4]string{"", "", "", ""}, Resolution{}, ErrNotFound},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.in[0], tt.in[1], tt.in[2], tt.in[3])
+		if got != tt.expected || !errors.Is(err, tt.err) {
+			t.Errorf("expected: %v, %v, got: %v, %v", tt.expected, tt.err, got, err)
+		}
+	}
+}
+
+func TestResolveInitFile(t *testing.T) {
+	defer Init("lib.add")
+	dataPath := writeTestData(t, map[string]string{"divisions.csv": "code,name,parent_code\n" +
+		"420000,湖北省,\n" +
+		"420100,武汉市,420000\n" +
+		"420106,武昌区,420100\n" +
+		"429000,省直辖县级行政区划,420000\n" +
+		"429004,仙桃市,429000\n" +
+		"500000,重庆市,\n" +
+		"500100,市辖区,500000\n" +
+		"500103,渝中区,500100\n"})
+	if err := InitFile(dataPath+"/divisions.csv", ""); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in       string
+		expected Resolution
+	}{
+		{"湖北省仙桃市钱沟路", Resolution{Address{"湖北省", "省直辖县级行政区划", "仙桃市", false}, "429004", "钱沟路"}},
+		{"仙桃市钱沟路", Resolution{Address{"湖北省", "省直辖县级行政区划", "仙桃市", false}, "429004", "钱沟路"}},
+		{"武汉武昌", Resolution{Address{"湖北省", "武汉市", "武昌区", false}, "420106", ""}},
+		{"重庆市渝中区", Resolution{Address{"重庆市", "重庆市", "渝中区", true}, "500103", ""}},
+	}
+	for _, tt := range tests {
+		got, err := Resolve("", "", "", tt.in)
+		if err != nil || got != tt.expected {
+			t.Errorf("expected: %s, %s, %s, got: %s, %s, %s, %v", tt.expected, tt.expected.Code, tt.expected.Detail,
+				got, got.Code, got.Detail, err)
+		}
+	}
+	if got := Suggest("湖北仙桃", 0); len(got) != 1 || got[0].Code != "429004" {
+		t.Errorf("expected: [429004], got: %v", got)
+	}
+}
@@ -0,0 +1,116 @@
+package addlib
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 地址区划. 查询函数返回的是副本, 修改它不会影响地址库.
+type Region struct {
+	Code       string // 编码, 例如: CN033001012
+	Name       string // 标准名称, 例如: 西湖区
+	ShortName  string // 简称, 例如: 西湖
+	Level      Level  // 统计区划级别
+	Kind       Kind   // 行政区划类型
+	ParentCode string // 父节点编码. 省的父节点编码为""
+	GBCode     string // 国家标准行政区划代码(GB/T 2260), 例如: 330106. 若数据中没有则为""
+}
+
+// 国家标准代码的数据文件(可选).
+// 格式: 第1列为编码, 第2列为国家标准代码(数字).
+const dataGBCode = "gbcodes.data"
+
+// 国家标准代码: 编码 -> 国家标准代码
+var libGBCodes = make(map[string]string)
+
+// 从数据文件加载国家标准代码.
+// 数据文件不存在时不报错.
+func loadGBCodes(filePath string, ptLibGBCodes *map[string]string) error {
+	if _, err := os.Stat(filePath); err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	lines, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+	for i, line := range lines {
+		lineNo := i + 1
+		row := strings.Split(line, "\t")
+		if len(row) != 2 || !isAllDigitAbc(row[0]) || !isAllDigitAbc(row[1]) {
+			detail := fmt.Sprintf("row = %s", row)
+			return &DataError{filePath, lineNo, detail, ErrDataFormat}
+		}
+		(*ptLibGBCodes)[row[0]] = row[1]
+	}
+	return nil
+}
+
+// 把libItem转换为Region
+func newRegion(item *libItem) Region {
+	parent := item.parent
+	if parent == ROOT {
+		parent = ""
+	}
+	return Region{
+		Code:       item.code,
+		Name:       item.name,
+		ShortName:  libShortNames[item.code],
+		Level:      item.level,
+		Kind:       item.kind,
+		ParentCode: parent,
+		GBCode:     libGBCodes[item.code],
+	}
+}
+
+// 输入编码, 输出地址区划
+// 若输入错误, 则返回空的Region和false
+func GetRegion(code string) (Region, bool) {
+	if item, ok := libItems[code]; ok && code != ROOT {
+		return newRegion(item), true
+	}
+	return Region{}, false
+}
+
+// 输入地址名称, 输出对应的地址区划
+// 查询规则与GetCode相同.
+func FindRegion(provinceName string, cityName string, districtName string) (Region, bool) {
+	return GetRegion(GetCode(provinceName, cityName, districtName))
+}
+
+// 输入地址名称, 输出对应的地址区划
+// 查询规则和错误与LookupCode相同.
+func LookupRegion(provinceName string, cityName string, districtName string) (Region, error) {
+	code, err := LookupCode(provinceName, cityName, districtName)
+	if err != nil {
+		return Region{}, err
+	}
+	region, _ := GetRegion(code)
+	return region, nil
+}
+
+// 输入编码, 输出它所管辖的地址区划
+// 若code为ROOT, 则输出所有省. 若输入错误, 则返回空[]
+func SubRegions(code string) []Region {
+	regions := make([]Region, 0)
+	if item, ok := libItems[code]; ok {
+		for _, child := range item.children {
+			regions = append(regions, newRegion(libItems[child]))
+		}
+	}
+	return regions
+}
+
+// 输入编码, 输出从省到自身的地址区划
+// 例: RegionPath(CN033001012) -> [浙江省 杭州市 西湖区]
+func RegionPath(code string) ([]Region, error) {
+	if _, ok := libItems[code]; !ok || code == ROOT {
+		return nil, lookupError("", code, ErrNotFound)
+	}
+	ancestors := Ancestors(code)
+	regions := make([]Region, 0, len(ancestors)+1)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		regions = append(regions, newRegion(libItems[ancestors[i]]))
+	}
+	return append(regions, newRegion(libItems[code])), nil
+}
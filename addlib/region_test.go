@@ -0,0 +1,122 @@
+package addlib
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestGetRegion(t *testing.T) {
+	got, ok := GetRegion("CN033001012")
+	expected := Region{"CN033001012", "西湖区", "西湖", LevelDistrict, KindUrbanDistrict, "CN033001000", ""}
+	if !ok || got != expected {
+		t.Errorf("expected: %v, got: %v", expected, got)
+	}
+	if _, ok := GetRegion("foo"); ok {
+		t.Errorf("expected: false, got: true")
+	}
+	if _, ok := GetRegion(ROOT); ok {
+		t.Errorf("expected: false, got: true")
+	}
+}
+
+func TestShortName(t *testing.T) {
+	tests := []struct {
+		inProvince string
+		inCity     string
+		inDistrict string
+		expected   string
+	}{
+		{"浙江", "", "", "浙江"},
+		{"北京", "", "", "北京"},
+		{"内蒙古", "", "", "内蒙古"},
+		{"香港", "", "", "香港"},
+		{"", "兴安盟", "", "兴安"},
+		{"", "阿克苏", "", "阿克苏"},
+		{"", "杭州", "建德", "建德"},
+		{"", "重庆", "忠县", "忠县"},
+		{"广西", "", "", "广西"},
+		{"新疆", "", "", "新疆"},
+		{"", "恩施", "", "恩施"},
+		{"", "黔东南", "", "黔东南"},
+		{"", "伊犁", "", "伊犁"},
+		{"", "克孜勒苏", "", "克州"},
+		{"", "洛阳", "瀍河", "瀍河"},
+		{"", "临夏", "东乡", "东乡"},
+		{"", "临夏", "积石山", "积石山"},
+		{"", "呼伦贝尔", "鄂伦春", "鄂伦春"},
+		{"", "松原", "前郭", "前郭"},
+	}
+
+	for _, tt := range tests {
+		got, _ := FindRegion(tt.inProvince, tt.inCity, tt.inDistrict)
+		if got.ShortName != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got.ShortName)
+		}
+	}
+}
+
+func TestSubRegions(t *testing.T) {
+	if got := len(SubRegions(ROOT)); got != 34 {
+		t.Errorf("expected: 34, got: %d", got)
+	}
+	for _, r := range SubRegions("CN033000000") {
+		if r.ParentCode != "CN033000000" || r.Level != LevelCity {
+			t.Errorf("unexpected region: %v", r)
+		}
+	}
+	if got := len(SubRegions("foo")); got != 0 {
+		t.Errorf("expected: 0, got: %d", got)
+	}
+}
+
+func TestRegionPath(t *testing.T) {
+	got, err := RegionPath("CN033001012")
+	if err != nil || len(got) != 3 {
+		t.Fatalf("expected 3 regions, got: %v, %v", got, err)
+	}
+	if got[0].Name != "浙江省" || got[1].Name != "杭州市" || got[2].Name != "西湖区" {
+		t.Errorf("unexpected path: %v", got)
+	}
+	if got[0].ParentCode != "" {
+		t.Errorf("expected empty parent code, got: %s", got[0].ParentCode)
+	}
+	if _, err := RegionPath("foo"); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestGBCodes(t *testing.T) {
+	defer Init("lib.add")
+	files := map[string]string{
+		dataProvince: "P1\t浙江省\n",
+		dataCity:     "P1\tC1\t杭州市\n",
+		dataDistrict: "C1\tD1\t西湖区\n",
+		dataGBCode:   "P1\t330000\nD1\t330106\n",
+	}
+	if err := Init(writeTestData(t, files)); err != nil {
+		t.Fatal(err)
+	}
+	check := func() {
+		for code, expected := range map[string]string{"P1": "330000", "C1": "", "D1": "330106"} {
+			if region, _ := GetRegion(code); region.GBCode != expected {
+				t.Errorf("code = %s, expected: %s, got: %s", code, expected, region.GBCode)
+			}
+		}
+	}
+	check()
+
+	// 快照包括国家标准代码
+	var buf bytes.Buffer
+	WriteSnapshot(&buf)
+	Init("lib.add")
+	if err := ReadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	check()
+
+	files[dataGBCode] = "P1\t33-0000\n"
+	if err := Init(writeTestData(t, files)); !errors.Is(err, ErrDataFormat) {
+		t.Errorf("expected: %v, got: %v", ErrDataFormat, err)
+	}
+}
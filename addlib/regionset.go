@@ -0,0 +1,205 @@
+package addlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// 区域集合的名称, 例如: 大陆(mainland), 港澳台(hmt).
+// 集合的成员可以是省编码, 也可以是市或区的编码.
+type RegionSet string
+
+const (
+	SetAll      RegionSet = "all"      // 全部地址, 不需要定义
+	SetMainland RegionSet = "mainland" // 大陆
+	SetHMT      RegionSet = "hmt"      // 港澳台
+)
+
+// 区域集合的数据文件(可选).
+// 格式: 第1列为集合名称(字母数字), 第2列为成员编码.
+const dataRegionSet = "regionsets.data"
+
+// 区域集合: 集合名称 -> {成员编码}
+var libSets = make(map[RegionSet]map[string]bool)
+
+// 保护libSets和libGroups. 它们在Init之后还可以被DefineRegionSet, DefineGroup和LoadGroups修改,
+// 因此与查询并发调用时需要加锁. 集合的成员一旦发布就不再修改, 修改集合时整体替换.
+var customMu sync.RWMutex
+
+// 港澳台的省名前缀, 用于数据中没有定义SetMainland和SetHMT时的内置集合
+var hmtPrefixes = []string{"香港", "澳门", "台湾"}
+
+// 从数据文件加载区域集合.
+// 数据文件不存在时不报错, 此时只有SetAll和内置的SetMainland, SetHMT可用(参考regionSetMembers).
+func loadRegionSets(filePath string, items map[string]*libItem, ptLibSets *map[RegionSet]map[string]bool) error {
+	if _, err := os.Stat(filePath); err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	lines, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+	for i, line := range lines {
+		lineNo := i + 1
+		row := strings.Split(line, "\t")
+		if len(row) != 2 || !isAllDigitAbc(row[0]) || !isAllDigitAbc(row[1]) {
+			detail := fmt.Sprintf("row = %s", row)
+			return &DataError{filePath, lineNo, detail, ErrDataFormat}
+		}
+		if _, ok := items[row[1]]; !ok {
+			detail := fmt.Sprintf("unknown code in region set, set = %s, code = %s", row[0], row[1])
+			return &DataError{filePath, lineNo, detail, ErrInconsistent}
+		}
+		set := RegionSet(row[0])
+		if _, ok := (*ptLibSets)[set]; !ok {
+			(*ptLibSets)[set] = make(map[string]bool)
+		}
+		(*ptLibSets)[set][row[1]] = true
+	}
+	return nil
+}
+
+// 自定义区域集合. 若集合已存在, 则覆盖.
+// 输入: set - 集合名称, codes - 成员编码(省市区均可)
+// 注意: Init会重置所有集合, 因此自定义集合需要在Init之后定义.
+func DefineRegionSet(set RegionSet, codes []string) error {
+	if set == "" || set == SetAll {
+		msg := fmt.Sprintf("invalid region set name, set = %s", set)
+		return errors.New(msg)
+	}
+	members := make(map[string]bool)
+	for _, code := range codes {
+		if _, ok := libItems[code]; !ok || code == ROOT {
+			return lookupError("", code, ErrNotFound)
+		}
+		members[code] = true
+	}
+	customMu.Lock()
+	libSets[set] = members
+	customMu.Unlock()
+	return nil
+}
+
+// 输出所有可用的区域集合名称(不包括SetAll). 数据中没有定义时, 也包括内置的SetMainland和SetHMT.
+func RegionSets() []RegionSet {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	sets := make([]RegionSet, 0)
+	for set := range libSets {
+		sets = append(sets, set)
+	}
+	for _, set := range []RegionSet{SetMainland, SetHMT} {
+		if _, ok := libSets[set]; !ok {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// 判断编码是否属于区域集合.
+// 规则: 编码自身, 它的祖先或它的后代是集合的成员.
+// 例如: 集合只包含杭州市, 则浙江省, 杭州市和西湖区都属于该集合, 而宁波市不属于.
+func InRegionSet(code string, set RegionSet) bool {
+	if _, ok := libItems[code]; !ok || code == ROOT {
+		return false
+	}
+	if set == SetAll {
+		return true
+	}
+	members, ok := regionSetMembers(set)
+	if !ok {
+		return false
+	}
+	// 自身或祖先
+	for c := code; c != ROOT; c = libItems[c].parent {
+		if members[c] {
+			return true
+		}
+	}
+	// 后代
+	for member := range members {
+		for c := member; c != ROOT; c = libItems[c].parent {
+			if c == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 输出区域集合的成员. 若集合未定义, 则返回false.
+// 数据中没有定义SetMainland或SetHMT时(例如用InitFile, InitDB初始化), 按省名区分港澳台.
+func regionSetMembers(set RegionSet) (map[string]bool, bool) {
+	customMu.RLock()
+	members, ok := libSets[set]
+	customMu.RUnlock()
+	if ok || (set != SetMainland && set != SetHMT) {
+		return members, ok
+	}
+	members = make(map[string]bool)
+	for _, code := range libItems[ROOT].children {
+		if isHMT(libItems[code].name) == (set == SetHMT) {
+			members[code] = true
+		}
+	}
+	return members, true
+}
+
+// 判断省名是否为港澳台
+func isHMT(name string) bool {
+	for _, prefix := range hmtPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 按区域集合过滤编码列表
+func filterByRegionSet(codes []string, set RegionSet) []string {
+	result := make([]string, 0)
+	for _, code := range codes {
+		if InRegionSet(code, set) {
+			result = append(result, code)
+		}
+	}
+	return result
+}
+
+// 按多个区域集合过滤编码列表, 只保留属于所有集合的编码
+func filterByRegionSets(codes []string, sets []RegionSet) []string {
+	for _, set := range sets {
+		codes = filterByRegionSet(codes, set)
+	}
+	return codes
+}
+
+// 输出区域集合中的省编码
+// 若集合未定义, 则返回空[]
+func ProvinceCodesIn(set RegionSet) []string {
+	return filterByRegionSet(libItems[ROOT].children, set)
+}
+
+// 地址树的节点
+type Node struct {
+	Code     string
+	Name     string
+	Children []Node
+}
+
+// 输出区域集合对应的地址树(省 -> 市 -> 区)
+func Tree(set RegionSet) []Node {
+	return buildTree(ROOT, set)
+}
+
+// 递归构造code的子树
+func buildTree(code string, set RegionSet) []Node {
+	nodes := make([]Node, 0)
+	for _, child := range filterByRegionSet(libItems[code].children, set) {
+		nodes = append(nodes, Node{child, GetName(child), buildTree(child, set)})
+	}
+	return nodes
+}
@@ -0,0 +1,135 @@
+package addlib
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestProvinceCodesIn(t *testing.T) {
+	tests := []struct {
+		in       RegionSet
+		expected int
+	}{
+		{SetAll, 34},
+		{SetMainland, 31},
+		{SetHMT, 3},
+		{"foo", 0},
+	}
+
+	for _, tt := range tests {
+		if got := len(ProvinceCodesIn(tt.in)); got != tt.expected {
+			t.Errorf("set: %s, expected: %d, got: %d", tt.in, tt.expected, got)
+		}
+	}
+}
+
+func TestBuiltinRegionSets(t *testing.T) {
+	defer Init("lib.add")
+	dataPath := writeTestData(t, map[string]string{"divisions.csv": "code,name,parent_code\n" +
+		"330000,浙江省,\n" +
+		"810000,香港特别行政区,\n" +
+		"820000,澳门特别行政区,\n" +
+		"710000,台湾省,\n" +
+		"330100,杭州市,330000\n"})
+	if err := InitFile(dataPath+"/divisions.csv", ""); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in       []string
+		expected []string
+	}{
+		{ProvinceCodes(true), []string{"330000"}},
+		{ProvinceCodes(false), []string{"330000", "810000", "820000", "710000"}},
+		{ProvinceCodesIn(SetHMT), []string{"810000", "820000", "710000"}},
+		{Provinces(true), []string{"浙江省"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.in, tt.expected) {
+			t.Errorf("expected: %v, got: %v", tt.expected, tt.in)
+		}
+	}
+	if !InRegionSet("330100", SetMainland) || InRegionSet("330100", SetHMT) {
+		t.Errorf("expected 330100 in %s only", SetMainland)
+	}
+	if got := len(RegionSets()); got != 2 {
+		t.Errorf("expected: 2, got: %d", got)
+	}
+}
+
+func TestDefineRegionSet(t *testing.T) {
+	codes := []string{GetCode("", "杭州", ""), GetCode("", "宁波", ""), GetCode("江苏", "", "")}
+	if err := DefineRegionSet("sales", codes); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		customMu.Lock()
+		delete(libSets, "sales")
+		customMu.Unlock()
+	}()
+
+	if got := Provinces(false, "sales"); len(got) != 2 {
+		t.Errorf("expected: 2, got: %d", len(got))
+	}
+	if got := Provinces(true, "sales", SetHMT); len(got) != 0 {
+		t.Errorf("expected: 0, got: %d", len(got))
+	}
+	if got := Cities("浙江", "sales"); len(got) != 2 {
+		t.Errorf("expected: 2, got: %d", len(got))
+	}
+	if got := Cities("江苏", "sales"); len(got) != len(Cities("江苏")) {
+		t.Errorf("expected: %d, got: %d", len(Cities("江苏")), len(got))
+	}
+	if got := Districts("杭州", "sales"); len(got) != 13 {
+		t.Errorf("expected: 13, got: %d", len(got))
+	}
+	if InRegionSet(GetCode("", "温州", ""), "sales") {
+		t.Errorf("expected: false, got: true")
+	}
+	if err := DefineRegionSet("bad", []string{"foo"}); err == nil {
+		t.Errorf("expected error for unknown code")
+	}
+	if err := DefineRegionSet(SetAll, codes); err == nil {
+		t.Errorf("expected error for reserved set name")
+	}
+}
+
+func TestTree(t *testing.T) {
+	tree := Tree(SetHMT)
+	if len(tree) != 3 {
+		t.Fatalf("expected: 3, got: %d", len(tree))
+	}
+	count := 0
+	for _, p := range Tree(SetAll) {
+		for _, c := range p.Children {
+			count += len(c.Children)
+		}
+	}
+	if count != 3207 {
+		t.Errorf("expected: 3207, got: %d", count)
+	}
+}
+
+func TestDefineRegionSetConcurrent(t *testing.T) {
+	defer Init("lib.add")
+	// 用go test -race检查
+	codes := []string{GetCode("浙江", "", "")}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				DefineRegionSet("sales", codes)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Provinces(false, "sales")
+				RegionSets()
+			}
+		}()
+	}
+	wg.Wait()
+}
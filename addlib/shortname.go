@@ -0,0 +1,196 @@
+package addlib
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 简称的数据文件(可选), 用于覆盖按规则生成的简称(参考shortName).
+// 格式: 第1列为编码, 第2列为简称(仅汉字). 例如: CN030010000	克州
+const dataShortName = "shortnames.data"
+
+// 所有区划的简称: 编码 -> 简称. 每次加载地址库时重新生成(参考buildShortNames).
+var libShortNames = make(map[string]string)
+
+// 少数民族的名称, 用于去掉自治地方名称中的民族(例如: 恩施土家族苗族自治州 -> 恩施).
+// 按长度降序排列, 保证先匹配较长的名称(例如: "柯尔克孜"要先于"孜"匹配).
+var ethnicGroups = []string{
+	"乌孜别克", "柯尔克孜",
+	"维吾尔", "哈萨克", "塔吉克", "俄罗斯", "鄂温克", "塔塔尔", "鄂伦春", "达斡尔",
+	"蒙古", "布依", "朝鲜", "土家", "哈尼", "傈僳", "高山", "拉祜", "东乡", "纳西", "景颇",
+	"仫佬", "布朗", "撒拉", "毛南", "仡佬", "锡伯", "阿昌", "普米", "德昂", "保安", "裕固",
+	"独龙", "赫哲", "门巴", "珞巴", "基诺",
+	"回", "藏", "苗", "彝", "壮", "满", "侗", "瑶", "白", "傣", "黎", "佤", "畲", "水", "土",
+	"羌", "怒", "京", "各",
+}
+
+// 按类型去掉标准名称的后缀和民族, 得到简称.
+// 例如: 浙江省 -> 浙江, 内蒙古自治区 -> 内蒙古, 西湖区 -> 西湖, 恩施土家族苗族自治州 -> 恩施, 顺河回族区 -> 顺河
+// 规则如下:
+// 1. 去掉与类型对应的后缀, 例如"自治州".
+// 2. 依次去掉末尾的"某族"(例如: 土家族, 苗族, 各族). 自治地方还去掉末尾不带"族"的名称(例如: 伊犁哈萨克自治州 -> 伊犁).
+// 3. 简称至少保留2个汉字. 若去掉民族后不足2个汉字(例如: 东乡族自治县), 则只去掉"族"; 若去掉后缀后不足2个汉字(例如: 忠县), 则返回标准名称.
+func shortName(name string, kind Kind) string {
+	suffix := kind.String()
+	switch kind {
+	case KindMunicipality, KindPrefectureCity, KindCountyCity:
+		suffix = "市"
+	case KindUrbanDistrict:
+		suffix = "区"
+	case KindUnknown, KindDirectlyAdministered:
+		return name
+	}
+	short := strings.TrimSuffix(name, suffix)
+	if len([]rune(short)) < 2 {
+		return name
+	}
+
+	autonomous := kind == KindAutonomousRegion || kind == KindAutonomousPrefecture ||
+		kind == KindAutonomousCounty || kind == KindAutonomousBanner
+	for {
+		trimmed := trimEthnicGroup(short, autonomous)
+		if trimmed == short {
+			break
+		}
+		if len([]rune(trimmed)) < 2 {
+			if rest := strings.TrimSuffix(short, "族"); len([]rune(rest)) >= 2 {
+				short = rest
+			}
+			break
+		}
+		short = trimmed
+	}
+	return short
+}
+
+// 去掉末尾的一个民族. 若bare为true, 则也去掉不带"族"的名称.
+// 若末尾不是民族, 则返回原来的名称.
+func trimEthnicGroup(name string, bare bool) string {
+	for _, group := range ethnicGroups {
+		if strings.HasSuffix(name, group+"族") {
+			return strings.TrimSuffix(name, group+"族")
+		}
+	}
+	if bare {
+		for _, group := range ethnicGroups {
+			if len([]rune(group)) >= 2 && strings.HasSuffix(name, group) {
+				return strings.TrimSuffix(name, group)
+			}
+		}
+	}
+	return name
+}
+
+// 输入编码, 输出简称. 例如: CN019000000 -> 内蒙古
+// 若输入错误, 则返回"".
+func GetShortName(code string) string {
+	if code == ROOT {
+		return ""
+	}
+	return libShortNames[code]
+}
+
+// 从数据文件加载覆盖的简称.
+// 数据文件不存在时不报错.
+func loadShortNames(filePath string, ptOverrides *map[string]string) error {
+	if _, err := os.Stat(filePath); err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	lines, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+	for i, line := range lines {
+		row := strings.Split(line, "\t")
+		if len(row) != 2 || !isAllDigitAbc(row[0]) || row[1] == "" || !isAllHanChar(row[1]) {
+			detail := fmt.Sprintf("row = %s", row)
+			return &DataError{filePath, i + 1, detail, ErrDataFormat}
+		}
+		(*ptOverrides)[row[0]] = row[1]
+	}
+	return nil
+}
+
+// 生成所有区划的简称. overrides中的简称优先, 其它按规则生成.
+func buildShortNames(items map[string]*libItem, overrides map[string]string) map[string]string {
+	names := make(map[string]string, len(items))
+	for code, item := range items {
+		if code == ROOT {
+			continue
+		}
+		if name, ok := overrides[code]; ok {
+			names[code] = name
+		} else {
+			names[code] = shortName(item.name, item.kind)
+		}
+	}
+	return names
+}
+
+// 输出与规则生成的简称不同的简称(即数据文件中覆盖的简称), 用于写入快照.
+func shortNameOverrides() map[string]string {
+	overrides := make(map[string]string)
+	for code, name := range libShortNames {
+		if item := libItems[code]; name != shortName(item.name, item.kind) {
+			overrides[code] = name
+		}
+	}
+	return overrides
+}
+
+// 把简称加入中文索引, 使GetCode等函数可以用简称查询, 例如: GetCityCode("克州").
+// 只加入无法通过原来的索引查询到的简称, 并且同一个范围(省, 市或某个市的区)内不能有其它名称以它开头, 否则会影响其它名称的查询.
+func indexShortNames(items map[string]*libItem, index map[string]string, shortNames map[string]string) {
+	// 范围 -> 该范围内的编码
+	scopes := make(map[string][]string)
+	for code, item := range items {
+		if code == ROOT || item.kind == KindDirectlyAdministered {
+			continue
+		}
+		scope := indexPrefix(item)
+		scopes[scope] = append(scopes[scope], code)
+	}
+	for _, scope := range sortedKeys(scopes) {
+		codes := scopes[scope]
+		for _, code := range codes {
+			short := shortNames[code]
+			key, err := formatKey(scope, short, len([]rune(short)))
+			if err != nil || index[key] == code || resolvesTo(index, scope, short, code) {
+				continue
+			}
+			if _, ok := index[key]; ok {
+				continue
+			}
+			unique := true
+			for _, other := range codes {
+				if other != code && (strings.HasPrefix(items[other].name, short) || strings.HasPrefix(shortNames[other], short)) {
+					unique = false
+					break
+				}
+			}
+			if unique {
+				index[key] = code
+			}
+		}
+	}
+}
+
+// 中文索引的前缀: 省为"province", 市为"city", 区为所属市的编码(参考initLibIndex)
+func indexPrefix(item *libItem) string {
+	if item.level == LevelDistrict {
+		return item.parent
+	}
+	return string(item.level)
+}
+
+// 判断按lookupIndex的规则查询name是否得到code
+func resolvesTo(index map[string]string, prefix string, name string, code string) bool {
+	for keySize := 2; keySize <= len([]rune(name)); keySize++ {
+		key, _ := formatKey(prefix, name, keySize)
+		if c, ok := index[key]; ok {
+			return c == code
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package addlib
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestShortNameRules(t *testing.T) {
+	tests := []struct {
+		inName   string
+		inKind   Kind
+		expected string
+	}{
+		{"内蒙古自治区", KindAutonomousRegion, "内蒙古"},
+		{"恩施土家族苗族自治州", KindAutonomousPrefecture, "恩施"},
+		{"伊犁哈萨克自治州", KindAutonomousPrefecture, "伊犁"},
+		{"隆林各族自治县", KindAutonomousCounty, "隆林"},
+		{"东乡族自治县", KindAutonomousCounty, "东乡"},
+		{"鄂温克族自治旗", KindAutonomousBanner, "鄂温克"},
+		{"鄂伦春自治旗", KindAutonomousBanner, "鄂伦春"},
+		{"顺河回族区", KindUrbanDistrict, "顺河"},
+		{"蒙古族区", KindUrbanDistrict, "蒙古"},
+		{"建德市", KindCountyCity, "建德"},
+		{"忠县", KindCounty, "忠县"},
+	}
+
+	for _, tt := range tests {
+		if got := shortName(tt.inName, tt.inKind); got != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestGetShortName(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{"CN019000000", "内蒙古"},
+		{"CN013001000", "恩施"},
+		{"CN030010000", "克州"},
+		{"CN033001012", "西湖"},
+		{ROOT, ""},
+		{"foo", ""},
+	}
+
+	for _, tt := range tests {
+		if got := GetShortName(tt.in); got != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestShortNameIndex(t *testing.T) {
+	tests := []struct {
+		inProvince string
+		inCity     string
+		inDistrict string
+		expected   string
+	}{
+		{"", "恩施", "", "CN013001000"},
+		{"新疆", "克州", "", "CN030010000"},
+		{"新疆", "博州", "", "CN030004000"},
+		{"", "松原", "前郭", "CN017006004"},
+		{"", "朝阳", "喀左", "CN018003004"},
+	}
+
+	for _, tt := range tests {
+		if got := GetCode(tt.inProvince, tt.inCity, tt.inDistrict); got != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got)
+		}
+	}
+	if got, detail, err := ParseText("克州阿图什市光明路"); err != nil || got.City != "克孜勒苏柯尔克孜自治州" || detail != "光明路" {
+		t.Errorf("expected: 克孜勒苏柯尔克孜自治州 光明路, got: %s %s, %v", got.City, detail, err)
+	}
+}
+
+func TestShortNameData(t *testing.T) {
+	defer Init("lib.add")
+	dataPath := writeTestData(t, map[string]string{
+		dataProvince:  "P1\t新疆维吾尔自治区\n",
+		dataCity:      "P1\tC1\t克孜勒苏柯尔克孜自治州\nP1\tC2\t博尔塔拉蒙古自治州\nP1\tC3\t博乐市\n",
+		dataDistrict:  "C1\tD1\t阿图什市\nC2\tD2\t精河县\nC3\tD3\t博乐市\n",
+		dataShortName: "C1\t克州\nC2\t博\n",
+	})
+	if err := Init(dataPath); err != nil {
+		t.Fatal(err)
+	}
+	check := func() {
+		tests := []struct {
+			in       string
+			expected string
+		}{
+			{GetShortName("P1"), "新疆"},
+			{GetShortName("C1"), "克州"},
+			{GetShortName("C2"), "博"},
+			{GetCityCode("克州"), "C1"},
+			// 博乐市也以"博"开头, 不能用简称查询
+			{GetCityCode("博"), ""},
+		}
+		for _, tt := range tests {
+			if tt.in != tt.expected {
+				t.Errorf("expected: %s, got: %s", tt.expected, tt.in)
+			}
+		}
+	}
+	check()
+
+	// 快照包括覆盖的简称
+	var buf bytes.Buffer
+	WriteSnapshot(&buf)
+	Init("lib.add")
+	if err := ReadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	check()
+
+	dataPath = writeTestData(t, map[string]string{
+		dataProvince:  "P1\t新疆维吾尔自治区\n",
+		dataCity:      "P1\tC1\t克孜勒苏柯尔克孜自治州\n",
+		dataDistrict:  "C1\tD1\t阿图什市\n",
+		dataShortName: "C1\tKezhou\n",
+	})
+	if err := Init(dataPath); !errors.Is(err, ErrDataFormat) {
+		t.Errorf("expected: %v, got: %v", ErrDataFormat, err)
+	}
+}
@@ -0,0 +1,327 @@
+package addlib
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+)
+
+// 快照: 预编译的地址库(地址树, 中文索引, 区域集合, 分组, 国家标准代码, 其它语言的名称和简称).
+// 加载快照不需要解析数据文件和递归建立索引(参考autoIndex), 适合启动频繁的命令行工具和云函数.
+//
+// 文件格式(整数均为小端序):
+// 1. 文件头(20字节): 魔数"ADDLIBSS"(8字节), 版本(uint16), 保留(uint16), 数据长度(uint32), 数据的CRC32校验和(uint32).
+// 2. 数据: 依次为地址树, 中文索引, 有歧义的索引, 区域集合, 分组, 国家标准代码, 其它语言的名称和覆盖的简称(参考shortNameOverrides). 字符串和列表均以uvarint长度开头.
+const (
+	snapshotMagic      = "ADDLIBSS"
+	snapshotVersion    = 5
+	snapshotHeaderSize = 20
+)
+
+// 把当前的地址库写入快照
+func WriteSnapshot(w io.Writer) error {
+	var buf snapshotWriter
+
+	codes := sortedKeys(libItems)
+	buf.uvarint(len(codes))
+	for _, code := range codes {
+		item := libItems[code]
+		buf.str(item.code)
+		buf.str(item.name)
+		buf.str(item.parent)
+		buf.str(string(item.level))
+		buf.uvarint(int(item.kind))
+		buf.strs(item.children)
+	}
+
+	keys := sortedKeys(libIndex)
+	buf.uvarint(len(keys))
+	for _, key := range keys {
+		buf.str(key)
+		buf.str(libIndex[key])
+	}
+	buf.strs(sortedKeys(libAmbiguous))
+
+	customMu.RLock()
+	sets := sortedKeys(libSets)
+	buf.uvarint(len(sets))
+	for _, set := range sets {
+		buf.str(string(set))
+		buf.strs(sortedKeys(libSets[set]))
+	}
+
+	schemes := sortedKeys(libGroups)
+	buf.uvarint(len(schemes))
+	for _, scheme := range schemes {
+		s := libGroups[scheme]
+		buf.str(scheme)
+		buf.uvarint(len(s.groups))
+		for _, group := range s.groups {
+			buf.str(group)
+			buf.strs(s.members[group])
+		}
+	}
+	customMu.RUnlock()
+
+	gbCodes := sortedKeys(libGBCodes)
+	buf.uvarint(len(gbCodes))
+	for _, code := range gbCodes {
+		buf.str(code)
+		buf.str(libGBCodes[code])
+	}
+
+	languages := sortedKeys(libTranslations)
+	buf.uvarint(len(languages))
+	for _, language := range languages {
+		names := libTranslations[language]
+		buf.str(language)
+		buf.uvarint(len(names))
+		for _, code := range sortedKeys(names) {
+			buf.str(code)
+			buf.str(names[code])
+		}
+	}
+
+	overrides := shortNameOverrides()
+	buf.uvarint(len(overrides))
+	for _, code := range sortedKeys(overrides) {
+		buf.str(code)
+		buf.str(overrides[code])
+	}
+
+	payload := buf.Bytes()
+	header := make([]byte, snapshotHeaderSize)
+	copy(header, snapshotMagic)
+	binary.LittleEndian.PutUint16(header[8:], snapshotVersion)
+	binary.LittleEndian.PutUint32(header[12:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(header[16:], crc32.ChecksumIEEE(payload))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// 把当前的地址库写入快照文件. 先写临时文件再替换, 写入失败时不破坏原来的文件.
+func SaveSnapshot(filePath string) error {
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf); err != nil {
+		return err
+	}
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return &DataError{filePath, 0, "cannot write snapshot", err}
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return &DataError{filePath, 0, "cannot write snapshot", err}
+	}
+	return nil
+}
+
+// 从快照文件初始化地址库. 失败时保留原来的地址库.
+// 若文件头, 版本或校验和不对, 则返回ErrDataFormat.
+// 注意: 快照不会随数据文件自动更新, 数据文件修改后需要重新生成(参考addlib snapshot命令).
+func InitSnapshot(filePath string) error {
+	data, release, err := readSnapshotFile(filePath)
+	if err != nil {
+		return &DataError{filePath, 0, "cannot open snapshot", err}
+	}
+	defer release()
+	if err := loadSnapshot(data); err != nil {
+		return dataError(filePath, 0, err)
+	}
+	return nil
+}
+
+// 从r读取快照并初始化地址库, 例如用go:embed嵌入程序中的快照. 失败时保留原来的地址库.
+func ReadSnapshot(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return &DataError{"", 0, "cannot read snapshot", err}
+	}
+	return loadSnapshot(data)
+}
+
+// 优先从快照文件初始化, 若快照不存在或无效, 则从数据文件夹初始化(参考Init).
+func InitWithSnapshot(snapshotPath string, dataPath string) error {
+	if err := InitSnapshot(snapshotPath); err == nil {
+		return nil
+	}
+	return Init(dataPath)
+}
+
+// 校验并解码快照, 全部成功后再替换全局变量.
+// 字符串均复制一份, 因此解码后可以释放data(例如munmap).
+func loadSnapshot(data []byte) error {
+	if len(data) < snapshotHeaderSize || string(data[:8]) != snapshotMagic {
+		return &DataError{"", 0, "not a snapshot", ErrDataFormat}
+	}
+	if version := binary.LittleEndian.Uint16(data[8:]); version != snapshotVersion {
+		detail := fmt.Sprintf("unsupported snapshot version, version = %d, expected %d", version, snapshotVersion)
+		return &DataError{"", 0, detail, ErrDataFormat}
+	}
+	payload := data[snapshotHeaderSize:]
+	if size := binary.LittleEndian.Uint32(data[12:]); int(size) != len(payload) {
+		detail := fmt.Sprintf("truncated snapshot, size = %d, expected %d", len(payload), size)
+		return &DataError{"", 0, detail, ErrDataFormat}
+	}
+	if binary.LittleEndian.Uint32(data[16:]) != crc32.ChecksumIEEE(payload) {
+		return &DataError{"", 0, "checksum mismatch", ErrDataFormat}
+	}
+
+	r := snapshotReader{data: payload}
+	items := make(map[string]*libItem)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		item := &libItem{code: r.str(), name: r.str(), parent: r.str()}
+		item.level = Level(r.str())
+		item.kind = Kind(r.uvarint())
+		item.children = r.strs()
+		items[item.code] = item
+	}
+	index := make(map[string]string)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		key := r.str()
+		index[key] = r.str()
+	}
+	ambiguous := make(map[string]bool)
+	for _, key := range r.strs() {
+		ambiguous[key] = true
+	}
+	sets := make(map[RegionSet]map[string]bool)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		set := RegionSet(r.str())
+		sets[set] = make(map[string]bool)
+		for _, code := range r.strs() {
+			sets[set][code] = true
+		}
+	}
+	groups := make(map[string]*groupScheme)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		s := &groupScheme{make([]string, 0), make(map[string][]string)}
+		groups[r.str()] = s
+		for m := r.uvarint(); m > 0 && r.err == nil; m-- {
+			group := r.str()
+			s.groups = append(s.groups, group)
+			s.members[group] = r.strs()
+		}
+	}
+	gbCodes := make(map[string]string)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		code := r.str()
+		gbCodes[code] = r.str()
+	}
+	translations := make(map[string]map[string]string)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		names := make(map[string]string)
+		translations[r.str()] = names
+		for m := r.uvarint(); m > 0 && r.err == nil; m-- {
+			code := r.str()
+			names[code] = r.str()
+		}
+	}
+	overrides := make(map[string]string)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		code := r.str()
+		overrides[code] = r.str()
+	}
+	if r.err == nil && r.off != len(payload) {
+		r.err = fmt.Errorf("%d trailing bytes", len(payload)-r.off)
+	}
+	if r.err != nil {
+		return &DataError{"", 0, r.err.Error(), ErrDataFormat}
+	}
+	if _, ok := items[ROOT]; !ok {
+		return &DataError{"", 0, "missing root node", ErrDataFormat}
+	}
+
+	// 简称的索引已经在中文索引中, 只需要重新生成简称.
+	shortNames := buildShortNames(items, overrides)
+
+	libItems, libIndex, libAmbiguous = items, index, ambiguous
+	libShortNames = shortNames
+	libNames, libNameSize = buildMatchNames(items, index, shortNames)
+	customMu.Lock()
+	libSets, libGroups = sets, groups
+	customMu.Unlock()
+	libGBCodes = gbCodes
+	libTranslations = translations
+	return nil
+}
+
+// 输出map的key, 按升序排列. 保证同一个地址库生成的快照完全相同.
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// 快照的编码器
+type snapshotWriter struct {
+	bytes.Buffer
+}
+
+func (w *snapshotWriter) uvarint(n int) {
+	w.Write(binary.AppendUvarint(nil, uint64(n)))
+}
+
+func (w *snapshotWriter) str(s string) {
+	w.uvarint(len(s))
+	w.WriteString(s)
+}
+
+func (w *snapshotWriter) strs(list []string) {
+	w.uvarint(len(list))
+	for _, s := range list {
+		w.str(s)
+	}
+}
+
+// 快照的解码器. 出错后err不为nil, 之后的读取均返回零值.
+type snapshotReader struct {
+	data []byte
+	off  int
+	err  error
+}
+
+func (r *snapshotReader) uvarint() int {
+	if r.err != nil {
+		return 0
+	}
+	n, size := binary.Uvarint(r.data[r.off:])
+	if size <= 0 || n > uint64(len(r.data)) {
+		r.err = fmt.Errorf("corrupted snapshot at offset %d", r.off)
+		return 0
+	}
+	r.off += size
+	return int(n)
+}
+
+func (r *snapshotReader) str() string {
+	n := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	if n > len(r.data)-r.off {
+		r.err = fmt.Errorf("corrupted snapshot at offset %d", r.off)
+		return ""
+	}
+	s := string(r.data[r.off : r.off+n])
+	r.off += n
+	return s
+}
+
+func (r *snapshotReader) strs() []string {
+	list := make([]string, 0)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		list = append(list, r.str())
+	}
+	return list
+}
@@ -0,0 +1,30 @@
+//go:build mmap && unix
+
+package addlib
+
+import (
+	"os"
+	"syscall"
+)
+
+// 用内存映射读取快照文件, 避免把整个文件复制到堆上.
+// 输出的release用于解除映射, 调用后不能再访问data.
+func readSnapshotFile(filePath string) ([]byte, func(), error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 {
+		return []byte{}, func() {}, nil
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() { syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,14 @@
+//go:build !mmap || !unix
+
+package addlib
+
+import "os"
+
+// 读取快照文件. 用-tags mmap编译时改为内存映射(参考snapshot_mmap.go).
+func readSnapshotFile(filePath string) ([]byte, func(), error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() {}, nil
+}
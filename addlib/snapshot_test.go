@@ -0,0 +1,111 @@
+package addlib
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	defer Init("lib.add")
+	var expected bytes.Buffer
+	if err := WriteSnapshot(&expected); err != nil {
+		t.Fatal(err)
+	}
+	snapshotPath := path.Join(t.TempDir(), "lib.snapshot")
+	if err := SaveSnapshot(snapshotPath); err != nil {
+		t.Fatal(err)
+	}
+
+	dataPath := writeTestData(t, map[string]string{"data.csv": "code,name,parent\n330000,浙江省,\n"})
+	if err := InitFile(dataPath+"/data.csv", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitSnapshot(snapshotPath); err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	WriteSnapshot(&got)
+	if !bytes.Equal(got.Bytes(), expected.Bytes()) {
+		t.Errorf("snapshot changed after reload")
+	}
+
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{GetCode("浙江", "杭州", "西湖"), "CN033001012"},
+		{GetName("CN034002023"), "渝中区"},
+		{Parent("CN033001000"), "CN033000000"},
+	}
+	for _, tt := range tests {
+		if tt.in != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, tt.in)
+		}
+	}
+	if _, err := LookupCityCode("张家"); !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("expected: %v, got: %v", ErrAmbiguous, err)
+	}
+	if len(ProvinceCodesIn(SetMainland)) != 31 {
+		t.Errorf("expected: 31, got: %d", len(ProvinceCodesIn(SetMainland)))
+	}
+	if got := RegionGroup("CN033001012", SchemeGeo); len(got) != 1 || got[0] != "华东" {
+		t.Errorf("expected: [华东], got: %s", got)
+	}
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	defer Init("lib.add")
+	var buf bytes.Buffer
+	WriteSnapshot(&buf)
+	data := buf.Bytes()
+
+	corrupt := func(f func(b []byte) []byte) string {
+		b := f(append([]byte(nil), data...))
+		filePath := path.Join(t.TempDir(), "lib.snapshot")
+		os.WriteFile(filePath, b, 0644)
+		return filePath
+	}
+	tests := []string{
+		corrupt(func(b []byte) []byte { return b[:10] }),
+		corrupt(func(b []byte) []byte { b[0] = 'X'; return b }),
+		corrupt(func(b []byte) []byte { b[8]++; return b }),
+		corrupt(func(b []byte) []byte { b[len(b)-1]++; return b }),
+		corrupt(func(b []byte) []byte { return b[:len(b)-1] }),
+	}
+	for _, filePath := range tests {
+		if err := InitSnapshot(filePath); !errors.Is(err, ErrDataFormat) {
+			t.Errorf("expected: %v, got: %v", ErrDataFormat, err)
+		}
+	}
+	if got := GetName("CN033001012"); got != "西湖区" {
+		t.Errorf("expected library unchanged after error, got: %s", got)
+	}
+
+	if err := InitWithSnapshot(path.Join(t.TempDir(), "missing"), "lib.add"); err != nil {
+		t.Errorf("expected fallback to data files, got: %v", err)
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	defer Init("lib.add")
+	var buf bytes.Buffer
+	WriteSnapshot(&buf)
+	data := buf.Bytes()
+
+	dataPath := writeTestData(t, map[string]string{"data.csv": "code,name,parent\n330000,浙江省,\n"})
+	if err := InitFile(dataPath+"/data.csv", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReadSnapshot(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if got := GetCode("浙江", "杭州", "西湖"); got != "CN033001012" {
+		t.Errorf("expected: %s, got: %s", "CN033001012", got)
+	}
+	if err := ReadSnapshot(bytes.NewReader(data[:10])); !errors.Is(err, ErrDataFormat) {
+		t.Errorf("expected: %v, got: %v", ErrDataFormat, err)
+	}
+}
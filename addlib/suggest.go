@@ -0,0 +1,61 @@
+package addlib
+
+import (
+	"sort"
+	"strings"
+)
+
+// 输入未完成的地址文本, 输出候选的区划, 用于地址输入框的自动补全.
+// 规则如下:
+// 1. 先按ParseText解析文本开头的完整名称, 作为上下文; 剩余的部分作为未完成的名称. 若解析失败(例如名称有歧义), 则整个文本都作为未完成的名称.
+// 2. 在上下文的后代中查找标准名称或简称以未完成的名称开头的区划, 级别高的在前, 同一级别按地址树的顺序排列.
+// 3. 若没有未完成的名称, 则输出上下文本身和它的下一级. 文本为空时输出所有的省.
+// 例: Suggest("浙江杭", 10) -> [杭州市], Suggest("西湖", 10) -> 所有的西湖区
+// 若limit <= 0, 则不限制个数.
+func Suggest(text string, limit int) []Region {
+	context, partial := ROOT, strings.TrimSpace(text)
+	if _, code, detail, err := parseText(partial); err == nil {
+		context = code
+		partial = strings.TrimSpace(detail)
+	}
+
+	codes := make([]string, 0)
+	if partial == "" {
+		if context != ROOT {
+			codes = append(codes, context)
+		}
+		codes = append(codes, libItems[context].children...)
+	} else {
+		for _, code := range Descendants(context, 0) {
+			item := libItems[code]
+			if strings.HasPrefix(item.name, partial) || strings.HasPrefix(libShortNames[code], partial) {
+				codes = append(codes, code)
+			}
+		}
+		sort.SliceStable(codes, func(i, j int) bool {
+			return levelDepth(libItems[codes[i]].level) < levelDepth(libItems[codes[j]].level)
+		})
+	}
+
+	if limit > 0 && len(codes) > limit {
+		codes = codes[:limit]
+	}
+	regions := make([]Region, len(codes))
+	for i, code := range codes {
+		regions[i] = newRegion(libItems[code])
+	}
+	return regions
+}
+
+// 级别的深度: 省为1, 市为2, 区为3
+func levelDepth(level Level) int {
+	switch level {
+	case LevelProvince:
+		return 1
+	case LevelCity:
+		return 2
+	case LevelDistrict:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package addlib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	names := func(regions []Region) string {
+		list := make([]string, len(regions))
+		for i, region := range regions {
+			list[i] = region.Name
+		}
+		return strings.Join(list, ",")
+	}
+	tests := []struct {
+		text     string
+		limit    int
+		expected string
+	}{
+		{"浙江杭", 10, "杭州市"},
+		{"杭州西", 10, "西湖区"},
+		{"杭州 西", 10, "西湖区"},
+		{"西湖", 0, "西湖区,西湖乡,西湖区"},
+		{"浙江", 3, "浙江省,杭州市,湖州市"},
+		{"浙江杭州不存在", 10, ""},
+		{"", 2, "安徽省,澳门特别行政区"},
+	}
+	for _, test := range tests {
+		if got := names(Suggest(test.text, test.limit)); got != test.expected {
+			t.Errorf("text = %s, expected: %s, got: %s", test.text, test.expected, got)
+		}
+	}
+
+	// 级别高的在前
+	regions := Suggest("吉林", 0)
+	for i := 1; i < len(regions); i++ {
+		if levelDepth(regions[i-1].Level) > levelDepth(regions[i].Level) {
+			t.Errorf("expected sorted by level, got: %s", names(regions))
+			break
+		}
+	}
+}
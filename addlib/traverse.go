@@ -0,0 +1,100 @@
+package addlib
+
+import "iter"
+
+// 输入编码, 输出父节点的编码
+// 省的父节点是ROOT, 此时返回"". 若输入错误, 也返回"".
+func Parent(code string) string {
+	if item, ok := libItems[code]; ok && item.parent != ROOT {
+		return item.parent
+	}
+	return ""
+}
+
+// 输入编码, 输出所有祖先的编码(由近及远, 不包括ROOT)
+// 例: Ancestors(CN033001012) -> [CN033001000 CN033000000]
+func Ancestors(code string) []string {
+	ancestors := make([]string, 0)
+	for c := Parent(code); c != ""; c = Parent(c) {
+		ancestors = append(ancestors, c)
+	}
+	return ancestors
+}
+
+// 输入编码, 按深度优先的顺序输出所有后代的编码
+// maxDepth为最大深度, 子节点的深度为1. 若maxDepth <= 0, 则不限制深度.
+// 若code为ROOT, 则输出整棵地址树.
+func Descendants(code string, maxDepth int) []string {
+	descendants := make([]string, 0)
+	Walk(code, func(c string, depth int) bool {
+		if depth == 0 {
+			return true
+		}
+		descendants = append(descendants, c)
+		return maxDepth <= 0 || depth < maxDepth
+	})
+	return descendants
+}
+
+// 输入编码, 输出兄弟节点的编码(不包括自身)
+// 例如: 杭州市的兄弟节点是浙江省的其它市.
+func Siblings(code string) []string {
+	siblings := make([]string, 0)
+	item, ok := libItems[code]
+	if !ok || code == ROOT {
+		return siblings
+	}
+	for _, c := range libItems[item.parent].children {
+		if c != code {
+			siblings = append(siblings, c)
+		}
+	}
+	return siblings
+}
+
+// 从root开始按深度优先的顺序访问地址树, root的深度为0.
+// 对每个节点调用fn, 若fn返回false, 则不再访问该节点的后代.
+// 若root不存在, 则不调用fn.
+func Walk(root string, fn func(code string, depth int) bool) {
+	if _, ok := libItems[root]; !ok {
+		return
+	}
+	walk(root, 0, fn)
+}
+
+func walk(code string, depth int, fn func(code string, depth int) bool) {
+	if code != ROOT && !fn(code, depth) {
+		return
+	}
+	for _, child := range libItems[code].children {
+		walk(child, depth+1, fn)
+	}
+}
+
+// 输出以root为根的子树迭代器, 依次产生(编码, 深度), 顺序与Walk相同.
+// 例:
+//
+//	for code, depth := range Traverse(GetCode("浙江", "", "")) {
+//		...
+//	}
+func Traverse(root string) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		if _, ok := libItems[root]; !ok {
+			return
+		}
+		traverse(root, 0, yield)
+	}
+}
+
+// 递归访问子树. 返回false表示迭代已经被调用方终止.
+func traverse(code string, depth int, yield func(string, int) bool) bool {
+	if code != ROOT && !yield(code, depth) {
+		return false
+	}
+	for _, child := range libItems[code].children {
+		if !traverse(child, depth+1, yield) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package addlib
+
+import "testing"
+
+func TestParent(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{"CN033001012", "CN033001000"},
+		{"CN033001000", "CN033000000"},
+		{"CN033000000", ""},
+		{"foo", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Parent(tt.in); got != tt.expected {
+			t.Errorf("expected: %s, got: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	got := Ancestors("CN033001012")
+	if len(got) != 2 || got[0] != "CN033001000" || got[1] != "CN033000000" {
+		t.Errorf("expected: [CN033001000 CN033000000], got: %s", got)
+	}
+	if got := Ancestors("CN033000000"); len(got) != 0 {
+		t.Errorf("expected: [], got: %s", got)
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	tests := []struct {
+		in       string
+		maxDepth int
+		expected int
+	}{
+		{"CN033000000", 1, 11},
+		{"CN033001000", 0, 13},
+		{ROOT, 1, 34},
+		{ROOT, 2, 34 + 367},
+		{ROOT, 0, 34 + 367 + 3207},
+		{"foo", 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := len(Descendants(tt.in, tt.maxDepth)); got != tt.expected {
+			t.Errorf("expected: %d, got: %d", tt.expected, got)
+		}
+	}
+}
+
+func TestSiblings(t *testing.T) {
+	if got := len(Siblings("CN033001000")); got != 10 {
+		t.Errorf("expected: 10, got: %d", got)
+	}
+	if got := len(Siblings("CN033000000")); got != 33 {
+		t.Errorf("expected: 33, got: %d", got)
+	}
+	if got := len(Siblings("foo")); got != 0 {
+		t.Errorf("expected: 0, got: %d", got)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	counts := make(map[int]int)
+	Walk("CN033000000", func(code string, depth int) bool {
+		counts[depth]++
+		return GetLevel(code) != LevelCity || code == "CN033001000"
+	})
+	if counts[0] != 1 || counts[1] != 11 || counts[2] != 13 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestTraverse(t *testing.T) {
+	n := 0
+	for code, depth := range Traverse("CN033001000") {
+		if depth == 1 && Parent(code) != "CN033001000" {
+			t.Errorf("unexpected parent: %s", Parent(code))
+		}
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Errorf("expected: 5, got: %d", n)
+	}
+}
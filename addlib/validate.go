@@ -0,0 +1,206 @@
+package addlib
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// 数据文件中的一行地址数据
+type dataRow struct {
+	filePath string
+	line     int
+	level    Level
+	parent   string // 省的parent为ROOT
+	code     string
+	name     string
+}
+
+// 转换为数据文件中的列
+func (r dataRow) columns() []string {
+	if r.level == LevelProvince {
+		return []string{r.code, r.name}
+	}
+	return []string{r.parent, r.code, r.name}
+}
+
+// 可选数据文件的格式: 文件名, 列数, 编码所在的列
+var optionalDataFiles = []struct {
+	name       string
+	columns    int
+	codeColumn int
+}{
+	{dataRegionSet, 2, 1},
+	{dataGroup, 3, 2},
+	{dataGBCode, 2, 0},
+	{dataNameEnglish, 2, 0},
+	{dataNamePinyin, 2, 0},
+	{dataShortName, 2, 0},
+}
+
+// 校验数据文件, 输出所有的问题(而不是在第一个问题处停止).
+// 检查的内容包括:
+// 1. 数据文件是否存在, 能否读取
+// 2. 每行的格式: 列数, 编码(字母数字), 名称(仅汉字)
+// 3. 编码重复
+// 4. 父节点不存在(孤儿), 或者父节点的级别不对(例如区的父节点是省)
+// 5. 父子关系存在环
+// 6. 名称无法建立中文索引(参考autoIndex), 例如两个名称完全相同, 或者一个名称是另一个名称的前缀
+// 7. 可选数据文件(regionsets.data, groups.data, gbcodes.data, names_en.data, names_pinyin.data, shortnames.data)的格式, 以及引用的编码是否存在. names_en.data和names_pinyin.data只能包括省级编码
+// 若没有问题, 则返回空[]
+func Validate(dataPath string) []*DataError {
+	rows, problems := readDataRows(dataPath, false)
+	return append(problems, validateRows(dataPath, rows)...)
+}
+
+// 校验格式正确的行(Validate中的第3~7项).
+func validateRows(dataPath string, rows []dataRow) []*DataError {
+	problems := make([]*DataError, 0)
+	codes := make(map[string]dataRow)
+	valid := make([]dataRow, 0, len(rows))
+
+	// 编码重复
+	for _, row := range rows {
+		if first, ok := codes[row.code]; ok {
+			detail := fmt.Sprintf("duplicate code, code = %s, first defined in %s:%d",
+				row.code, path.Base(first.filePath), first.line)
+			problems = append(problems, &DataError{row.filePath, row.line, detail, ErrInconsistent})
+			continue
+		}
+		codes[row.code] = row
+		valid = append(valid, row)
+	}
+
+	// 孤儿和父节点的级别
+	parentLevel := map[Level]Level{LevelCity: LevelProvince, LevelDistrict: LevelCity}
+	for _, row := range valid {
+		if row.level == LevelProvince {
+			continue
+		}
+		parent, ok := codes[row.parent]
+		if !ok {
+			detail := fmt.Sprintf("parent code not found, code = %s, parent = %s", row.code, row.parent)
+			problems = append(problems, &DataError{row.filePath, row.line, detail, ErrInconsistent})
+		} else if parent.level != parentLevel[row.level] {
+			detail := fmt.Sprintf("parent level is %s, expected %s, code = %s, parent = %s",
+				parent.level, parentLevel[row.level], row.code, row.parent)
+			problems = append(problems, &DataError{row.filePath, row.line, detail, ErrInconsistent})
+		}
+	}
+
+	// 环
+	for _, row := range valid {
+		if cycle := findCycle(row.code, codes); cycle != nil {
+			detail := fmt.Sprintf("cycle detected, %s", strings.Join(cycle, " -> "))
+			problems = append(problems, &DataError{row.filePath, row.line, detail, ErrInconsistent})
+		}
+	}
+
+	// 中文索引. 与loadSingleDataFile相同, 按文件和行的顺序建立索引.
+	index := make(map[string]string)
+	indexCache := make(map[string]string)
+	for _, row := range valid {
+		if err := initLibIndex(&index, &indexCache, row.columns(), row.level); err != nil {
+			problems = append(problems, &DataError{row.filePath, row.line, err.Error(), ErrDataFormat})
+		}
+	}
+
+	// 可选数据文件
+	for _, file := range optionalDataFiles {
+		filePath := path.Join(dataPath, file.name)
+		if _, err := os.Stat(filePath); err != nil && os.IsNotExist(err) {
+			continue
+		}
+		lines, err := readLines(filePath)
+		if err != nil {
+			problems = append(problems, err.(*DataError))
+			continue
+		}
+		for i, line := range lines {
+			row := strings.Split(line, "\t")
+			if len(row) != file.columns {
+				detail := fmt.Sprintf("expected %d columns, got %d, row = %s", file.columns, len(row), row)
+				problems = append(problems, &DataError{filePath, i + 1, detail, ErrDataFormat})
+				continue
+			}
+			r, ok := codes[row[file.codeColumn]]
+			if !ok {
+				detail := fmt.Sprintf("unknown code, code = %s", row[file.codeColumn])
+				problems = append(problems, &DataError{filePath, i + 1, detail, ErrInconsistent})
+				continue
+			}
+			// 只能翻译省级名称(参考isProvinceLevel)
+			if file.name == dataNameEnglish || file.name == dataNamePinyin {
+				parentKind := kindOf(LevelProvince, codes[r.parent].name, KindUnknown)
+				if r.level != LevelProvince && kindOf(r.level, r.name, parentKind) != KindMunicipality {
+					detail := fmt.Sprintf("only province names can be translated, code = %s", r.code)
+					problems = append(problems, &DataError{filePath, i + 1, detail, ErrDataFormat})
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// 读取省市区数据文件, 输出格式正确的行和格式错误的行.
+// 若normalize为true, 则先去掉每列首尾的空白字符, 并跳过空行(参考Repair).
+func readDataRows(dataPath string, normalize bool) ([]dataRow, []*DataError) {
+	rows := make([]dataRow, 0)
+	problems := make([]*DataError, 0)
+	for _, file := range []string{dataProvince, dataCity, dataDistrict} {
+		filePath := path.Join(dataPath, file)
+		if err := checkData(dataPath, []string{file}); err != nil {
+			problems = append(problems, err.(*DataError))
+			continue
+		}
+		lines, err := readLines(filePath)
+		if err != nil {
+			problems = append(problems, err.(*DataError))
+			continue
+		}
+		level, _ := parseLevelFromFilePath(filePath)
+		for i, line := range lines {
+			if normalize && strings.TrimSpace(line) == "" {
+				continue
+			}
+			columns := strings.Split(line, "\t")
+			if normalize {
+				columns = normalizeColumns(columns)
+			}
+			if problem := rowProblem(columns, level); problem != "" {
+				detail := fmt.Sprintf("level = %s, %s", level, problem)
+				problems = append(problems, &DataError{filePath, i + 1, detail, ErrDataFormat})
+				continue
+			}
+			row := dataRow{filePath, i + 1, level, ROOT, columns[0], columns[1]}
+			if level != LevelProvince {
+				row.parent, row.code, row.name = columns[0], columns[1], columns[2]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, problems
+}
+
+// 从code开始沿父节点向上查找, 若回到已经访问过的节点, 则输出环上的编码.
+// 仅当code在环上时输出, 否则返回nil.
+func findCycle(code string, codes map[string]dataRow) []string {
+	visited := make(map[string]bool)
+	chain := make([]string, 0)
+	for c := code; ; {
+		if visited[c] {
+			if c != code {
+				return nil
+			}
+			return append(chain, c)
+		}
+		row, ok := codes[c]
+		if !ok || row.level == LevelProvince {
+			return nil
+		}
+		visited[c] = true
+		chain = append(chain, c)
+		c = row.parent
+	}
+}
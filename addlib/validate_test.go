@@ -0,0 +1,89 @@
+package addlib
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+// 把数据写入临时文件夹, 输出文件夹路径
+func writeTestData(t *testing.T, files map[string]string) string {
+	dataPath := t.TempDir()
+	for name, data := range files {
+		if err := os.WriteFile(path.Join(dataPath, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dataPath
+}
+
+func TestValidate(t *testing.T) {
+	if problems := Validate("lib.add"); len(problems) != 0 {
+		t.Errorf("expected no problems, got: %v", problems)
+	}
+
+	dataPath := writeTestData(t, map[string]string{
+		dataProvince: "P1\t浙江省\nP2\tZhejiang\nP1\t江苏省\n",
+		dataCity:     "P1\tC1\t杭州市\nP9\tC2\t宁波市\nC3\tC3\t温州市\n",
+		dataDistrict: "C1\tD1\t西湖区\nC1\tD2\t西湖区\nP1\tD3\t余杭区\nC1\tD4\nC1\tD5\t上城\nC1\tD6\t上城区\n",
+		dataGroup:    "geo\t华东\tP1\ngeo\t华东\tP9\n",
+	})
+	expected := []struct {
+		file string
+		line int
+		err  error
+		text string
+	}{
+		{dataProvince, 2, ErrDataFormat, "not all Han"},
+		{dataDistrict, 4, ErrDataFormat, "expected 3 columns"},
+		{dataProvince, 3, ErrInconsistent, "duplicate code"},
+		{dataCity, 2, ErrInconsistent, "parent code not found"},
+		{dataCity, 3, ErrInconsistent, "parent level is city"},
+		{dataDistrict, 3, ErrInconsistent, "parent level is province"},
+		{dataCity, 3, ErrInconsistent, "cycle detected"},
+		{dataDistrict, 2, ErrDataFormat, "identical items"},
+		{dataDistrict, 6, ErrDataFormat, "sub-name exists"},
+		{dataGroup, 2, ErrInconsistent, "unknown code"},
+	}
+
+	problems := Validate(dataPath)
+	if len(problems) != len(expected) {
+		t.Errorf("expected %d problems, got: %d", len(expected), len(problems))
+	}
+	for _, e := range expected {
+		found := false
+		for _, p := range problems {
+			if path.Base(p.Path) == e.file && p.Line == e.line && errors.Is(p, e.err) &&
+				strings.Contains(p.Detail, e.text) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected problem %s:%d %s, got: %v", e.file, e.line, e.text, problems)
+		}
+	}
+}
+
+// 重名的判断与编码的长度无关
+func TestValidateIdenticalNames(t *testing.T) {
+	for _, codes := range [][]string{{"P1", "C1", "D1", "D2"}, {"CN033000000", "CN033001000", "CN033001012", "CN033001013"}} {
+		dataPath := writeTestData(t, map[string]string{
+			dataProvince: codes[0] + "\t浙江省\n",
+			dataCity:     codes[0] + "\t" + codes[1] + "\t杭州市\n",
+			dataDistrict: codes[1] + "\t" + codes[2] + "\t西湖区\n" + codes[1] + "\t" + codes[3] + "\t西湖区\n",
+		})
+		problems := Validate(dataPath)
+		if len(problems) != 1 || !strings.Contains(problems[0].Detail, "identical items") {
+			t.Errorf("codes: %s, expected identical items, got: %v", codes, problems)
+		}
+	}
+}
+
+func TestValidateMissingFiles(t *testing.T) {
+	problems := Validate(t.TempDir())
+	if len(problems) != 3 {
+		t.Errorf("expected 3 problems, got: %v", problems)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * addlib.so的C调用示例, 同时检查内存是否泄漏(make valgrind).
+ * 用法: ./example [数据文件夹] [循环次数]
+ * 每个返回char*的函数的结果都必须用freeString释放, 参考addlib.h中的"内存管理".
+ */
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+#include "addlib.h"
+
+static int failures = 0;
+
+/* 比较结果并释放 */
+static void check(const char *name, char *got, const char *expected) {
+    if (got == NULL) {
+        fprintf(stderr, "%s: expected: %s, got: NULL\n", name, expected);
+        failures++;
+        return;
+    }
+    if (expected != NULL && strcmp(got, expected) != 0) {
+        fprintf(stderr, "%s: expected: %s, got: %s\n", name, expected, got);
+        failures++;
+    }
+    freeString(got);
+}
+
+/* 调用所有导出的函数 */
+static void run(void) {
+    check("provinces", provinces(1), NULL);
+    check("cities", cities("浙江"), NULL);
+    check("districts", districts("杭州"), NULL);
+    check("getName", getName("CN033001012"), "西湖区");
+    check("parseAddress", parseAddress("", "杭州", "西湖"), "浙江省\t杭州市\t西湖区");
+    check("parseAddress", parseAddress("", "", "不存在"), "");
+    check("provinceCodes", provinceCodes(0), NULL);
+    check("cityCodes", cityCodes("CN033000000"), NULL);
+    check("districtCodes", districtCodes("CN033001000"), NULL);
+    check("getCode", getCode("浙江", "杭州", "西湖"), "CN033001012");
+    check("getProvinceCode", getProvinceCode("浙江"), "CN033000000");
+    check("getCityCode", getCityCode("杭州"), "CN033001000");
+    check("getDistrictCode", getDistrictCode("杭州", "西湖"), "CN033001012");
+    check("parseCode", parseCode("CN033001012"), "CN033000000\tCN033001000\tCN033001012");
+    check("parseCode", parseCode("foo"), "");
+
+    /* JSON接口 */
+    check("getNameJSON", getNameJSON("CN033001012"), "{\"result\":\"西湖区\",\"error\":null}");
+    check("getNameJSON", getNameJSON(""),
+          "{\"result\":null,\"error\":{\"code\":\"bad_input\",\"message\":\"bad input: empty input\"}}");
+    check("getNameJSON", getNameJSON("foo"),
+          "{\"result\":null,\"error\":{\"code\":\"not_found\",\"message\":\"not found, input = foo\"}}");
+    check("getCodeJSON", getCodeJSON("浙江", "杭州", "西湖"), "{\"result\":\"CN033001012\",\"error\":null}");
+    check("getCodeJSON", getCodeJSON("江苏", "杭州", ""),
+          "{\"result\":null,\"error\":{\"code\":\"inconsistent\",\"message\":\"inconsistent address, level = city, input = 杭州\"}}");
+    check("parseAddressJSON", parseAddressJSON("", "杭州", "西湖"),
+          "{\"result\":{\"province\":\"浙江省\",\"city\":\"杭州市\",\"district\":\"西湖区\",\"municipality\":false,"
+          "\"code\":\"CN033001012\"},\"error\":null}");
+    check("parseCodeJSON", parseCodeJSON("CN033001012"),
+          "{\"result\":{\"province_code\":\"CN033000000\",\"city_code\":\"CN033001000\",\"district_code\":\"CN033001012\"},"
+          "\"error\":null}");
+    check("parseTextJSON", parseTextJSON("浙江省杭州市西湖区文三路100号"),
+          "{\"result\":{\"province\":\"浙江省\",\"city\":\"杭州市\",\"district\":\"西湖区\",\"municipality\":false,"
+          "\"code\":\"CN033001012\",\"detail\":\"文三路100号\"},\"error\":null}");
+    check("getRegionJSON", getRegionJSON("CN033001012"), NULL);
+    check("regionPathJSON", regionPathJSON("CN033001012"), NULL);
+    check("provincesJSON", provincesJSON(1), NULL);
+    check("citiesJSON", citiesJSON("浙江"), NULL);
+    check("districtCodesJSON", districtCodesJSON("CN033000000"),
+          "{\"result\":null,\"error\":{\"code\":\"bad_input\",\"message\":\"bad input: expected a city code, got: CN033000000\"}}");
+    check("batchJSON", batchJSON("getCode", "[[\"浙江\", \"杭州\", \"西湖\"], [\"\", \"foo\", \"\"], 1]"),
+          "{\"result\":[{\"result\":\"CN033001012\",\"error\":null},"
+          "{\"result\":null,\"error\":{\"code\":\"not_found\",\"message\":\"not found, level = city, input = foo\"}},"
+          "{\"result\":null,\"error\":{\"code\":\"bad_input\",\"message\":\"bad input: item must be a string or an array of strings\"}}],"
+          "\"error\":null}");
+    check("batchJSON", batchJSON("getName", "[\"CN033001012\"]"),
+          "{\"result\":[{\"result\":\"西湖区\",\"error\":null}],\"error\":null}");
+    check("batchJSON", batchJSON("getName", "{"),
+          "{\"result\":null,\"error\":{\"code\":\"bad_input\",\"message\":\"bad input: unexpected end of JSON input\"}}");
+    check("suggestJSON", suggestJSON("浙江杭", 10), NULL);
+    check("formatJSON", formatJSON("CN033001012", "short", ""), "{\"result\":\"浙江 杭州 西湖\",\"error\":null}");
+    check("formatJSON", formatJSON("CN033001012", "foo", ""),
+          "{\"result\":null,\"error\":{\"code\":\"bad_input\",\"message\":\"bad input: unknown style, style = foo\"}}");
+    check("regionSetsJSON", regionSetsJSON(), NULL);
+    check("inRegionSetJSON", inRegionSetJSON("CN033001012", "mainland"), "{\"result\":true,\"error\":null}");
+    check("provinceCodesInJSON", provinceCodesInJSON("foo"),
+          "{\"result\":null,\"error\":{\"code\":\"not_found\",\"message\":\"not found, set = foo\"}}");
+    check("schemesJSON", schemesJSON(), NULL);
+    check("groupsJSON", groupsJSON("geo"), NULL);
+    check("membersJSON", membersJSON("geo", "华北"), NULL);
+    check("regionGroupsJSON", regionGroupsJSON("CN033001012"), NULL);
+    check("parentJSON", parentJSON("CN033001012"), "{\"result\":\"CN033001000\",\"error\":null}");
+    check("ancestorsJSON", ancestorsJSON("CN033001012"), "{\"result\":[\"CN033001000\",\"CN033000000\"],\"error\":null}");
+    check("siblingsJSON", siblingsJSON("CN033001012"), NULL);
+    check("descendantsJSON", descendantsJSON("CN033000000", 1), NULL);
+    check("descendantsJSON", descendantsJSON("ROOT", 1), NULL);
+    check("descendantsJSON", descendantsJSON("foo", 1),
+          "{\"result\":null,\"error\":{\"code\":\"not_found\",\"message\":\"not found, input = foo\"}}");
+
+    /* 按行批量调用 */
+    check("getCodes", getCodes("浙江\t杭州\t西湖\n\t杭州\n\tfoo\t\r\n\n"),
+          "CN033001012\t\nCN033001000\t\n\tnot_found\n\tbad_input\n");
+    check("getCodes", getCodes(""), "");
+    check("getNames", getNames("CN033001012\nfoo"), "西湖区\t\n\tnot_found\n");
+    check("parseAddresses", parseAddresses("北京\t\t东城\n浙江\t杭州\t西湖\tfoo"),
+          "北京市\t北京市\t东城区\t1\tCN003001004\t\n\t\t\t\t\tbad_input\n");
+    check("parseCodes", parseCodes("CN033001012"), "CN033000000\tCN033001000\tCN033001012\t\n");
+    check("parseTexts", parseTexts("浙江省杭州市西湖区文三路\t100号"),
+          "浙江省\t杭州市\t西湖区\t0\tCN033001012\t文三路 100号\t\n");
+}
+
+int main(int argc, char **argv) {
+    char *dataPath = argc > 1 ? argv[1] : "../lib.add";
+    int loops = argc > 2 ? atoi(argv[2]) : 1;
+
+    char *err = initialize(dataPath);
+    if (err[0] != '\0') {
+        fprintf(stderr, "cannot load data: %s\n", err);
+        freeString(err);
+        return 1;
+    }
+    freeString(err);
+    freeString(NULL);
+
+    for (int i = 0; i < loops; i++) {
+        run();
+    }
+    if (failures > 0) {
+        fprintf(stderr, "%d failures\n", failures);
+        return 1;
+    }
+    printf("ok, loops = %d\n", loops);
+    return 0;
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"goAddLib/addlib"
+	"io"
+	"os"
+)
+
+// 导出地址树. 默认输出到标准输出.
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	format := flags.String("format", "cascader", "导出格式: cascader, mysql, postgres, sqlite(SQL脚本, 不生成.db文件)")
+	output := flags.String("o", "", "输出文件, 默认为标准输出")
+	set := flags.String("set", string(addlib.SetAll), "区域集合")
+	depth := flags.Int("depth", 0, "最大层数, 0表示不限制")
+	value := flags.String("value", "value", "编码的字段名")
+	label := flags.String("label", "label", "名称的字段名")
+	children := flags.String("children", "children", "孩子的字段名")
+	indent := flags.String("indent", "", "JSON的缩进")
+	table := flags.String("table", "divisions", "SQL的表名")
+	drop := flags.Bool("drop", false, "SQL脚本先删除已存在的表")
+	flags.Parse(args)
+
+	if !initLib(*dataPath) {
+		return 1
+	}
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cannot create output:", err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	var err error
+	switch *format {
+	case "cascader":
+		opts := addlib.CascaderOptions{
+			ValueField:    *value,
+			LabelField:    *label,
+			ChildrenField: *children,
+			Set:           addlib.RegionSet(*set),
+			MaxDepth:      *depth,
+			Indent:        *indent,
+		}
+		err = addlib.ExportCascader(w, opts)
+	case "mysql", "postgres", "sqlite":
+		opts := addlib.SQLOptions{
+			Dialect:   addlib.Dialect(*format),
+			Table:     *table,
+			DropTable: *drop,
+			Set:       addlib.RegionSet(*set),
+			MaxDepth:  *depth,
+		}
+		err = addlib.ExportSQL(w, opts)
+	default:
+		fmt.Fprintln(os.Stderr, "unknown format:", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cannot export:", err)
+		return 1
+	}
+	return 0
+}
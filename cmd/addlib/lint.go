@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"goAddLib/addlib"
+	"os"
+)
+
+// 检查数据文件的格式和命名, 每个问题输出一行. 若有问题, 则退出码为1.
+// 若指定-fix, 则先把数据文件重写为规范格式(去掉多余的空白字符, 空行和重复行), 再检查剩余的问题. -sort同时按编码排序.
+func runLint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	fix := flags.Bool("fix", false, "把数据文件重写为规范格式")
+	sortRows := flags.Bool("sort", false, "-fix时按编码排序")
+	flags.Parse(args)
+
+	if *fix {
+		if err := addlib.Repair(*dataPath, addlib.RepairOptions{Sort: *sortRows}); err != nil {
+			fmt.Fprintln(os.Stderr, "cannot repair:", err)
+			return 1
+		}
+	}
+	problems := addlib.Lint(*dataPath)
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "%d problem(s) found\n", len(problems))
+		return 1
+	}
+	return 0
+}
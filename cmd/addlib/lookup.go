@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"goAddLib/addlib"
+	"os"
+)
+
+// 通过地址名称查询编码, 输出编码和标准地址. 例: addlib lookup 浙江 杭州 西湖
+// 省市区名称可以用-province, -city, -district指定, 也可以按顺序输入(空名称用""表示).
+func runLookup(args []string) int {
+	flags := flag.NewFlagSet("lookup", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	province := flags.String("province", "", "省名")
+	city := flags.String("city", "", "市名")
+	district := flags.String("district", "", "区名")
+	flags.Parse(args)
+
+	names := []*string{province, city, district}
+	if flags.NArg() > len(names) {
+		fmt.Fprintln(os.Stderr, "too many names")
+		return 2
+	}
+	for i, name := range flags.Args() {
+		*names[i] = name
+	}
+	if !initLib(*dataPath) {
+		return 1
+	}
+	code, err := addlib.LookupCode(*province, *city, *district)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("%s\t%s\n", code, addressOf(code))
+	return 0
+}
+
+// 通过编码查询名称, 每个编码输出一行: 编码, 名称和标准地址. 例: addlib name CN033001012
+func runName(args []string) int {
+	flags := flag.NewFlagSet("name", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	flags.Parse(args)
+
+	if !initLib(*dataPath) {
+		return 1
+	}
+	status := 0
+	for _, code := range flags.Args() {
+		name, err := addlib.LookupName(code)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+			continue
+		}
+		fmt.Printf("%s\t%s\t%s\n", code, name, addressOf(code))
+	}
+	return status
+}
@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+		status   int
+	}{
+		{[]string{"浙江", "杭州", "西湖"}, "CN033001012\t浙江省杭州市西湖区\n", 0},
+		{[]string{"-city", "杭州", "-district", "西湖"}, "CN033001012\t浙江省杭州市西湖区\n", 0},
+		{[]string{"", "", "朝阳"}, "", 1},
+		{[]string{"", "foo"}, "", 1},
+		{[]string{"a", "b", "c", "d"}, "", 2},
+	}
+
+	for _, tt := range tests {
+		got, status := runCommand(t, runLookup, tt.args...)
+		if got != tt.expected || status != tt.status {
+			t.Errorf("args: %q, expected: %q %d, got: %q %d", tt.args, tt.expected, tt.status, got, status)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+		status   int
+	}{
+		{[]string{"CN033001012"}, "CN033001012\t西湖区\t浙江省杭州市西湖区\n", 0},
+		{[]string{"CN003001004"}, "CN003001004\t东城区\t北京市东城区\n", 0},
+		{[]string{"foo", "CN033000000"}, "CN033000000\t浙江省\t浙江省\n", 1},
+	}
+
+	for _, tt := range tests {
+		got, status := runCommand(t, runName, tt.args...)
+		if got != tt.expected || status != tt.status {
+			t.Errorf("args: %q, expected: %q %d, got: %q %d", tt.args, tt.expected, tt.status, got, status)
+		}
+	}
+}
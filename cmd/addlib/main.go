@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"goAddLib/addlib"
+	"os"
+	"sort"
+)
+
+// 子命令: 输入子命令的参数, 输出进程的退出码.
+type command struct {
+	usage string
+	run   func(args []string) int
+}
+
+var commands = map[string]command{
+	"validate":  {"validate [-data dir]: 校验数据文件, 输出所有问题", runValidate},
+	"lint":      {"lint [-data dir] [-fix]: 检查数据文件的规范格式和命名, -fix重写为规范格式", runLint},
+	"snapshot":  {"snapshot [-data dir] [-o file]: 从数据文件夹生成快照文件", runSnapshot},
+	"export":    {"export [-data dir] [-format cascader|mysql|postgres|sqlite] [-o file]: 导出地址树或SQL脚本", runExport},
+	"lookup":    {"lookup [-data dir] [province] [city] [district]: 通过地址名称查询编码", runLookup},
+	"name":      {"name [-data dir] code...: 通过编码查询名称", runName},
+	"tree":      {"tree [-data dir] [-depth n] [code|text]: 输出子树", runTree},
+	"parse":     {"parse [-data dir] [-json] [text...]: 解析自由文本中的地址, 没有参数时从标准输入读取", runParse},
+	"normalize": {"normalize [-data dir] [-format csv|tsv|jsonl] [-province col] [-city col] [-district col] [-text col] [file...]: 标准化表格中的地址", runNormalize},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: addlib <command> [arguments]")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  "+commands[name].usage)
+	}
+}
+
+// addlib命令行工具. 进入goAddLib的上级目录(GOPATH/src), 然后用如下命令安装:
+// go install goAddLib/cmd/addlib
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	os.Exit(cmd.run(os.Args[2:]))
+}
+
+// 初始化地址库. 若失败, 则输出错误并返回false.
+func initLib(dataPath string) bool {
+	if err := addlib.Init(dataPath); err != nil {
+		fmt.Fprintln(os.Stderr, "cannot load data:", err)
+		return false
+	}
+	return true
+}
+
+// 输入编码, 输出标准三级地址. 若编码不存在, 则返回空地址.
+func addressFields(code string) addlib.Address {
+	codes, _ := addlib.ParseCode(code)
+	return addlib.Address{
+		Province:     addlib.GetName(codes.ProvinceCode),
+		City:         addlib.GetName(codes.CityCode),
+		District:     addlib.GetName(codes.DistrictCode),
+		Municipality: addlib.GetKind(codes.ProvinceCode) == addlib.KindMunicipality,
+	}
+}
+
+// 输入编码, 输出完整的地址名称, 例如: 浙江省杭州市西湖区
+func addressOf(code string) string {
+	return addressFields(code).String()
+}
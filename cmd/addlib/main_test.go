@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+const testData = "../../lib.add"
+
+// 运行子命令, 输出标准输出的内容和退出码
+func runCommand(t *testing.T, run func(args []string) int, args ...string) (string, int) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	status := run(append([]string{"-data", testData}, args...))
+	w.Close()
+	os.Stdout = stdout
+	return <-output, status
+}
+
+// 用字符串代替标准输入
+func withStdin(t *testing.T, input string) {
+	t.Helper()
+	filePath := t.TempDir() + "/stdin"
+	if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = file
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		file.Close()
+	})
+}
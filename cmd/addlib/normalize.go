@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"goAddLib/addlib"
+	"io"
+	"os"
+	"slices"
+)
+
+// 标准化的输出列, 追加在输入列之后
+var normalizedColumns = []string{"norm_province", "norm_city", "norm_district", "norm_code", "norm_detail", "match_status", "match_error"}
+
+// 输入的地址列
+type addressColumns struct {
+	province, city, district, text string
+}
+
+// 读取CSV, TSV或JSON Lines, 解析指定的地址列, 输出原来的列和标准化的列(参考normalizedColumns).
+// match_status为ok或错误码(参考addlib.ErrorCodeOf): bad_input(地址列都为空, 或多出的列不为空), not_found, ambiguous或inconsistent.
+// 若没有输入文件, 则从标准输入读取. 多个CSV(TSV)文件的表头必须相同.
+// 例: addlib normalize -format csv -province 省 -city 市 -district 区 orders.csv > orders.norm.csv
+func runNormalize(args []string) int {
+	flags := flag.NewFlagSet("normalize", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	format := flags.String("format", "csv", "输入和输出的格式: csv, tsv, jsonl")
+	var columns addressColumns
+	flags.StringVar(&columns.province, "province", "", "省名所在的列(CSV表头或JSON字段名)")
+	flags.StringVar(&columns.city, "city", "", "市名所在的列")
+	flags.StringVar(&columns.district, "district", "", "区名所在的列")
+	flags.StringVar(&columns.text, "text", "", "地址文本所在的列. 省市区名称都为空时按自由文本解析")
+	flags.Parse(args)
+
+	if columns == (addressColumns{}) {
+		fmt.Fprintln(os.Stderr, "no address column, use -province, -city, -district or -text")
+		return 2
+	}
+	if !initLib(*dataPath) {
+		return 1
+	}
+
+	inputs := make([]io.Reader, 0)
+	for _, filePath := range flags.Args() {
+		file, err := os.Open(filePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		inputs = append(inputs, file)
+	}
+	if len(inputs) == 0 {
+		inputs = append(inputs, os.Stdin)
+	}
+
+	output := bufio.NewWriter(os.Stdout)
+	defer output.Flush()
+	var err error
+	switch *format {
+	case "csv", "tsv":
+		err = normalizeCSV(inputs, output, *format == "tsv", columns)
+	case "jsonl":
+		err = normalizeJSONL(inputs, output, columns)
+	default:
+		fmt.Fprintln(os.Stderr, "unknown format:", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func normalizeCSV(inputs []io.Reader, output io.Writer, tsv bool, columns addressColumns) error {
+	writer := csv.NewWriter(output)
+	if tsv {
+		writer.Comma = '\t'
+	}
+	var header []string
+	for _, input := range inputs {
+		reader := csv.NewReader(input)
+		reader.FieldsPerRecord = -1
+		if tsv {
+			reader.Comma = '\t'
+			reader.LazyQuotes = true
+		}
+		h, err := reader.Read()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if header == nil {
+			header = h
+			writer.Write(append(slices.Clone(header), normalizedColumns...))
+		} else if !slices.Equal(header, h) {
+			return errors.New("input files have different headers")
+		}
+		indexes := [4]int{-1, -1, -1, -1}
+		for i, column := range []string{columns.province, columns.city, columns.district, columns.text} {
+			if column == "" {
+				continue
+			}
+			if indexes[i] = slices.Index(header, column); indexes[i] < 0 {
+				return fmt.Errorf("column not found, column = %s", column)
+			}
+		}
+
+		for {
+			row, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			row, res, err := addlib.ResolveRow(row, len(header), indexes)
+			writer.Write(append(row, normalizeAddress(res, err)...))
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func normalizeJSONL(inputs []io.Reader, output io.Writer, columns addressColumns) error {
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	for _, input := range inputs {
+		scanner := bufio.NewScanner(input)
+		scanner.Buffer(nil, 1<<20)
+		for line := 1; scanner.Scan(); line++ {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+			record := make(map[string]any)
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			field := func(key string) string {
+				s, _ := record[key].(string)
+				return s
+			}
+			row := []string{field(columns.province), field(columns.city), field(columns.district), field(columns.text)}
+			_, res, err := addlib.ResolveRow(row, len(row), [4]int{0, 1, 2, 3})
+			values := normalizeAddress(res, err)
+			for i, column := range normalizedColumns {
+				record[column] = values[i]
+			}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 输入一条地址的解析结果(参考addlib.ResolveRow), 输出标准化的列.
+func normalizeAddress(res addlib.Resolution, err error) []string {
+	if err != nil {
+		return []string{"", "", "", "", "", string(addlib.ErrorCodeOf(err)), err.Error()}
+	}
+	return []string{res.Province, res.City, res.District, res.Code, res.Detail, "ok", ""}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"goAddLib/addlib"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeCSV(t *testing.T) {
+	if err := addlib.Init(testData); err != nil {
+		t.Fatal(err)
+	}
+	columns := addressColumns{province: "province", city: "city", district: "district"}
+	tests := []struct {
+		input    string
+		tsv      bool
+		columns  addressColumns
+		expected string
+	}{
+		{"id,province,city,district\n1,,杭州,西湖\n2,,张家,\n3,,,\n", false, columns,
+			"id,province,city,district,norm_province,norm_city,norm_district,norm_code,norm_detail,match_status,match_error\n" +
+				"1,,杭州,西湖,浙江省,杭州市,西湖区,CN033001012,,ok,\n" +
+				"2,,张家,,,,,,,ambiguous,\"ambiguous name, level = city, input = 张家\"\n" +
+				"3,,,,,,,,,bad_input,bad input: empty address\n"},
+		// 列数与表头不同的行: 补齐; 多出的列不为空时不截断, 作为bad_input
+		{"id,province,city,district,note\n2,,杭州,西湖\n3,,杭州,西湖,a,b\n4,,杭州,西湖,a,\n", false, columns,
+			"id,province,city,district,note,norm_province,norm_city,norm_district,norm_code,norm_detail,match_status,match_error\n" +
+				"2,,杭州,西湖,,浙江省,杭州市,西湖区,CN033001012,,ok,\n" +
+				"3,,杭州,西湖,a,,,,,,bad_input,\"bad input: too many columns, header = 5, extra = b\"\n" +
+				"4,,杭州,西湖,a,浙江省,杭州市,西湖区,CN033001012,,ok,\n"},
+		{"id\taddr\n1\t浙江省杭州市西湖区文三路\n", true, addressColumns{text: "addr"},
+			"id\taddr\tnorm_province\tnorm_city\tnorm_district\tnorm_code\tnorm_detail\tmatch_status\tmatch_error\n" +
+				"1\t浙江省杭州市西湖区文三路\t浙江省\t杭州市\t西湖区\tCN033001012\t文三路\tok\t\n"},
+	}
+
+	for _, tt := range tests {
+		var got strings.Builder
+		if err := normalizeCSV([]io.Reader{strings.NewReader(tt.input)}, &got, tt.tsv, tt.columns); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != tt.expected {
+			t.Errorf("expected: %q, got: %q", tt.expected, got.String())
+		}
+	}
+
+	// 表头不同或缺少地址列
+	inputs := []io.Reader{strings.NewReader("id,city\n"), strings.NewReader("id,town\n")}
+	if err := normalizeCSV(inputs, io.Discard, false, addressColumns{city: "city"}); err == nil {
+		t.Errorf("expected error for different headers")
+	}
+	inputs = []io.Reader{strings.NewReader("id,city\n")}
+	if err := normalizeCSV(inputs, io.Discard, false, addressColumns{city: "市"}); err == nil {
+		t.Errorf("expected error for missing column")
+	}
+}
+
+func TestNormalizeJSONL(t *testing.T) {
+	if err := addlib.Init(testData); err != nil {
+		t.Fatal(err)
+	}
+	input := `{"id":1,"city":"杭州","district":"西湖"}` + "\n\n" + `{"id":2,"city":"foo"}` + "\n"
+	expected := `{"city":"杭州","district":"西湖","id":1,"match_error":"","match_status":"ok","norm_city":"杭州市",` +
+		`"norm_code":"CN033001012","norm_detail":"","norm_district":"西湖区","norm_province":"浙江省"}` + "\n" +
+		`{"city":"foo","id":2,"match_error":"not found, level = city, input = foo","match_status":"not_found","norm_city":"",` +
+		`"norm_code":"","norm_detail":"","norm_district":"","norm_province":""}` + "\n"
+	var got strings.Builder
+	err := normalizeJSONL([]io.Reader{strings.NewReader(input)}, &got, addressColumns{city: "city", district: "district"})
+	if err != nil || got.String() != expected {
+		t.Errorf("expected: %s, got: %s, %v", expected, got.String(), err)
+	}
+	if err := normalizeJSONL([]io.Reader{strings.NewReader("{")}, io.Discard, addressColumns{city: "city"}); err == nil {
+		t.Errorf("expected error for bad JSON")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"goAddLib/addlib"
+	"os"
+)
+
+// 解析自由文本中的地址, 每条文本输出一行: 省, 市, 区, 编码和详细地址(以Tab分隔).
+// 若没有输入文本参数, 则从标准输入逐行读取. 例: addlib parse 杭州市西湖区文三路
+func runParse(args []string) int {
+	flags := flag.NewFlagSet("parse", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	asJSON := flags.Bool("json", false, "输出JSON Lines")
+	flags.Parse(args)
+
+	if !initLib(*dataPath) {
+		return 1
+	}
+	texts := flags.Args()
+	if len(texts) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			texts = append(texts, scanner.Text())
+		}
+	}
+
+	status := 0
+	encoder := json.NewEncoder(os.Stdout)
+	for _, text := range texts {
+		res, err := addlib.Resolve("", "", "", text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", text, err)
+			status = 1
+			continue
+		}
+		add, code, detail := res.Address, res.Code, res.Detail
+		if *asJSON {
+			encoder.Encode(map[string]any{
+				"text": text, "province": add.Province, "city": add.City, "district": add.District,
+				"municipality": add.Municipality, "code": code, "detail": detail,
+			})
+			continue
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", add.Province, add.City, add.District, code, detail)
+	}
+	return status
+}
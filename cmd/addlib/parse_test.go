@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		args     []string
+		stdin    string
+		expected string
+		status   int
+	}{
+		{[]string{"浙江省杭州市西湖区文三路100号"}, "", "浙江省\t杭州市\t西湖区\tCN033001012\t文三路100号\n", 0},
+		{[]string{"-json", "北京市东城区"}, "",
+			`{"city":"北京市","code":"CN003001004","detail":"","district":"东城区","municipality":true,"province":"北京市","text":"北京市东城区"}` + "\n", 0},
+		{nil, "杭州西湖\nfoo\n", "浙江省\t杭州市\t西湖区\tCN033001012\t\n", 1},
+	}
+
+	for _, tt := range tests {
+		if tt.args == nil {
+			withStdin(t, tt.stdin)
+		}
+		got, status := runCommand(t, runParse, tt.args...)
+		if got != tt.expected || status != tt.status {
+			t.Errorf("args: %q, expected: %q %d, got: %q %d", tt.args, tt.expected, tt.status, got, status)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"goAddLib/addlib"
+	"os"
+)
+
+// 从数据文件夹生成快照文件(参考addlib.InitSnapshot).
+func runSnapshot(args []string) int {
+	flags := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	output := flags.String("o", "lib.snapshot", "快照文件")
+	flags.Parse(args)
+
+	if !initLib(*dataPath) {
+		return 1
+	}
+	if err := addlib.SaveSnapshot(*output); err != nil {
+		fmt.Fprintln(os.Stderr, "cannot write snapshot:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"goAddLib/addlib"
+	"os"
+	"strings"
+)
+
+// 输出子树, 每个节点一行: 缩进的名称和编码. 例: addlib tree -depth 1 浙江
+// 根节点可以是编码或地址文本, 默认输出整棵地址树.
+func runTree(args []string) int {
+	flags := flag.NewFlagSet("tree", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	depth := flags.Int("depth", 0, "最大深度, 0表示不限制")
+	flags.Parse(args)
+
+	if !initLib(*dataPath) {
+		return 1
+	}
+	root := addlib.ROOT
+	if flags.NArg() > 0 {
+		var err error
+		if root, err = resolveCode(flags.Arg(0)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	addlib.Walk(root, func(code string, d int) bool {
+		indent := d
+		if root == addlib.ROOT {
+			indent--
+		}
+		fmt.Printf("%s%s\t%s\n", strings.Repeat("  ", indent), addlib.GetName(code), code)
+		return *depth <= 0 || d < *depth
+	})
+	return 0
+}
+
+// 输入编码或地址文本, 输出编码. 地址文本取最具体的一级, 例如: "浙江杭州" -> 杭州市的编码
+func resolveCode(s string) (string, error) {
+	if _, ok := addlib.GetRegion(s); ok {
+		return s, nil
+	}
+	res, err := addlib.Resolve("", "", "", s)
+	if err != nil {
+		return "", err
+	}
+	return res.Code, nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+		lines    int
+		status   int
+	}{
+		{[]string{"-depth", "1"}, "安徽省\tCN001000000\n", 34, 0},
+		{[]string{"CN033001000"}, "杭州市\tCN033001000\n  滨江区\tCN033001001\n", 14, 0},
+		{[]string{"-depth", "1", "浙江"}, "浙江省\tCN033000000\n  杭州市\tCN033001000\n", 12, 0},
+		{[]string{"foo"}, "", 0, 1},
+	}
+
+	for _, tt := range tests {
+		got, status := runCommand(t, runTree, tt.args...)
+		if !strings.HasPrefix(got, tt.expected) || strings.Count(got, "\n") != tt.lines || status != tt.status {
+			t.Errorf("args: %q, expected: %q... %d lines %d, got: %q %d", tt.args, tt.expected, tt.lines, tt.status, got, status)
+		}
+	}
+}
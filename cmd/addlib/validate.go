@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"goAddLib/addlib"
+	"os"
+)
+
+// 校验数据文件, 每个问题输出一行. 若有问题, 则退出码为1.
+func runValidate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	dataPath := flags.String("data", "lib.add", "数据文件夹")
+	flags.Parse(args)
+
+	problems := addlib.Validate(*dataPath)
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "%d problem(s) found\n", len(problems))
+		return 1
+	}
+	return 0
+}
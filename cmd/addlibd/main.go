@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// 程序版本, 编译时可以用-ldflags "-X main.version=..."设置
+var version = "dev"
+
+// addlib的HTTP服务. 进入goAddLib的上级目录(GOPATH/src), 然后用如下命令安装:
+// go install goAddLib/cmd/addlibd
+// 收到SIGHUP时重新加载数据文件, 收到SIGINT或SIGTERM时等待处理中的请求结束后退出.
+// POST /reload默认禁用, 用-reload-token设置令牌后才能通过HTTP重新加载.
+func main() {
+	addr := flag.String("addr", ":8080", "监听地址")
+	dataPath := flag.String("data", "lib.add", "数据文件夹")
+	reloadToken := flag.String("reload-token", os.Getenv("ADDLIBD_RELOAD_TOKEN"), "POST /reload的令牌, 默认禁用该接口")
+	flag.Parse()
+
+	s, err := newServer(*dataPath)
+	if err != nil {
+		log.Fatalln("cannot load data:", err)
+	}
+	s.reloadToken = *reloadToken
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// ListenAndServe在Shutdown开始时立即返回, 需要等待Shutdown结束.
+	stopped := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		defer close(stopped)
+		for sig := range signals {
+			if sig == syscall.SIGHUP {
+				if err := s.reload(); err != nil {
+					log.Println("cannot reload data:", err)
+				} else {
+					log.Println("data reloaded, dataset =", s.version())
+				}
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			srv.Shutdown(ctx)
+			cancel()
+			return
+		}
+	}()
+
+	log.Printf("listening on %s, dataset = %s", *addr, s.version())
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatalln(err)
+	}
+	<-stopped
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"goAddLib/addlib"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 批量请求的最大条数和请求体的最大字节数
+const (
+	maxBatchSize = 10000
+	maxBodySize  = 8 << 20
+)
+
+// HTTP服务.
+// 地址库是全局变量, 重新加载时(addlib.Init)不能同时查询, 因此所有查询持有读锁, 重新加载持有写锁.
+type server struct {
+	dataPath    string
+	reloadToken string // POST /reload的令牌, 为""时禁用该接口
+
+	mu       sync.RWMutex
+	dataset  string    // 数据集版本: 地址库快照的SHA-256(前12位十六进制)
+	loadedAt time.Time // 数据集的加载时间
+}
+
+// 创建服务并加载数据
+func newServer(dataPath string) (*server, error) {
+	s := &server{dataPath: dataPath}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// 重新加载数据文件. 加载失败时保留原来的地址库.
+func (s *server) reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := addlib.Init(s.dataPath); err != nil {
+		return err
+	}
+	hash := sha256.New()
+	addlib.WriteSnapshot(hash)
+	s.dataset = hex.EncodeToString(hash.Sum(nil))[:12]
+	s.loadedAt = time.Now()
+	return nil
+}
+
+// 输出数据集版本
+func (s *server) version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dataset
+}
+
+// 注意: 本项目没有go.mod, 按GOPATH模式编译时ServeMux不支持"GET /path"和"{code}"形式的路由, 因此手动检查请求方法.
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", only("GET", s.handleHealth))
+	mux.HandleFunc("/version", only("GET", s.handleVersion))
+	mux.HandleFunc("/reload", only("POST", s.handleReload))
+
+	mux.HandleFunc("/provinces", only("GET", s.handleProvinces))
+	mux.HandleFunc("/cities", only("GET", s.handleCities))
+	mux.HandleFunc("/districts", only("GET", s.handleDistricts))
+	mux.HandleFunc("/code", only("GET", s.handleCode))
+	mux.HandleFunc("/address", only("GET", s.handleAddress))
+	mux.HandleFunc("/codes/", only("GET", s.handleParseCode))
+	mux.HandleFunc("/parse", only("GET", s.handleParseText))
+
+	mux.HandleFunc("/batch/code", only("POST", s.handleBatchCode))
+	mux.HandleFunc("/batch/address", only("POST", s.handleBatchAddress))
+	mux.HandleFunc("/batch/codes", only("POST", s.handleBatchParseCode))
+	mux.HandleFunc("/batch/parse", only("POST", s.handleBatchParseText))
+	return mux
+}
+
+// 只接受指定的请求方法, 其它方法返回405
+func only(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// 查询参数和批量请求的地址
+type addressQuery struct {
+	Province string `json:"province"`
+	City     string `json:"city"`
+	District string `json:"district"`
+}
+
+// 地址的响应
+type addressResult struct {
+	Province     string `json:"province"`
+	City         string `json:"city"`
+	District     string `json:"district"`
+	Municipality bool   `json:"municipality"`
+	Code         string `json:"code"`
+	Detail       string `json:"detail,omitempty"` // 自由文本中剩余的详细地址
+}
+
+// 编码的响应
+type codesResult struct {
+	ProvinceCode string `json:"province_code"`
+	CityCode     string `json:"city_code"`
+	DistrictCode string `json:"district_code"`
+}
+
+// 批量响应中的一条结果. 成功时Error为"", 失败时Result为null, Code为错误码(参考addlib.ErrorCodeOf).
+type batchItem struct {
+	Result any    `json:"result"`
+	Code   string `json:"code,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"version":   version,
+		"dataset":   s.dataset,
+		"loaded_at": s.loadedAt.Format(time.RFC3339),
+		"regions":   len(addlib.Descendants(addlib.ROOT, 0)),
+	})
+}
+
+// 重新加载数据文件. 需要在Authorization头中提供令牌: "Bearer <token>"; 没有设置令牌时返回403.
+func (s *server) handleReload(w http.ResponseWriter, r *http.Request) {
+	if s.reloadToken == "" {
+		writeError(w, http.StatusForbidden, errors.New("reload is disabled, use -reload-token or SIGHUP"))
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.reloadToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("invalid reload token"))
+		return
+	}
+	if err := s.reload(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.handleVersion(w, r)
+}
+
+func (s *server) handleProvinces(w http.ResponseWriter, r *http.Request) {
+	mainland, _ := strconv.ParseBool(r.URL.Query().Get("mainland"))
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, addlib.Provinces(mainland))
+}
+
+func (s *server) handleCities(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, addlib.Cities(r.URL.Query().Get("province")))
+}
+
+func (s *server) handleDistricts(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, addlib.Districts(r.URL.Query().Get("city")))
+}
+
+func (s *server) handleCode(w http.ResponseWriter, r *http.Request) {
+	q := queryAddress(r)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	code, err := addlib.LookupCode(q.Province, q.City, q.District)
+	respond(w, map[string]string{"code": code}, err)
+}
+
+func (s *server) handleAddress(w http.ResponseWriter, r *http.Request) {
+	q := queryAddress(r)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result, err := parseAddress(q)
+	respond(w, result, err)
+}
+
+func (s *server) handleParseCode(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result, err := parseCode(strings.TrimPrefix(r.URL.Path, "/codes/"))
+	respond(w, result, err)
+}
+
+func (s *server) handleParseText(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result, err := parseText(r.URL.Query().Get("text"))
+	respond(w, result, err)
+}
+
+func (s *server) handleBatchCode(w http.ResponseWriter, r *http.Request) {
+	var queries []addressQuery
+	if !readBatch(w, r, &queries) {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := make([]batchItem, len(queries))
+	for i, q := range queries {
+		code, err := addlib.LookupCode(q.Province, q.City, q.District)
+		items[i] = newBatchItem(map[string]string{"code": code}, err)
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *server) handleBatchAddress(w http.ResponseWriter, r *http.Request) {
+	var queries []addressQuery
+	if !readBatch(w, r, &queries) {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := make([]batchItem, len(queries))
+	for i, q := range queries {
+		items[i] = newBatchItem(parseAddress(q))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *server) handleBatchParseCode(w http.ResponseWriter, r *http.Request) {
+	var codes []string
+	if !readBatch(w, r, &codes) {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := make([]batchItem, len(codes))
+	for i, code := range codes {
+		items[i] = newBatchItem(parseCode(code))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *server) handleBatchParseText(w http.ResponseWriter, r *http.Request) {
+	var texts []string
+	if !readBatch(w, r, &texts) {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := make([]batchItem, len(texts))
+	for i, text := range texts {
+		items[i] = newBatchItem(parseText(text))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// 以下函数调用地址库, 调用方需要持有读锁.
+
+func parseAddress(q addressQuery) (any, error) {
+	add, err := addlib.ParseAddress(q.Province, q.City, q.District)
+	if err != nil {
+		return nil, err
+	}
+	code := addlib.GetCode(add.Province, add.City, add.District)
+	return newAddressResult(add, code, ""), nil
+}
+
+func parseCode(code string) (any, error) {
+	codes, err := addlib.ParseCode(code)
+	if err != nil {
+		return nil, err
+	}
+	return codesResult{codes.ProvinceCode, codes.CityCode, codes.DistrictCode}, nil
+}
+
+func parseText(text string) (any, error) {
+	res, err := addlib.Resolve("", "", "", text)
+	if err != nil {
+		return nil, err
+	}
+	return newAddressResult(res.Address, res.Code, res.Detail), nil
+}
+
+func newAddressResult(add addlib.Address, code string, detail string) addressResult {
+	return addressResult{add.Province, add.City, add.District, add.Municipality, code, detail}
+}
+
+func queryAddress(r *http.Request) addressQuery {
+	q := r.URL.Query()
+	return addressQuery{q.Get("province"), q.Get("city"), q.Get("district")}
+}
+
+// 读取批量请求的JSON数组. 若请求无效, 则写入错误响应并返回false.
+func readBatch(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	if n := batchLen(v); n > maxBatchSize {
+		writeError(w, http.StatusRequestEntityTooLarge, errors.New("too many items in batch, max = "+strconv.Itoa(maxBatchSize)))
+		return false
+	}
+	return true
+}
+
+func batchLen(v any) int {
+	switch v := v.(type) {
+	case *[]addressQuery:
+		return len(*v)
+	case *[]string:
+		return len(*v)
+	}
+	return 0
+}
+
+func newBatchItem(result any, err error) batchItem {
+	if err != nil {
+		return batchItem{nil, string(addlib.ErrorCodeOf(err)), err.Error()}
+	}
+	return batchItem{result, "", ""}
+}
+
+// 输出查询结果. 错误码(参考addlib.ErrorCodeOf)对应的状态码: 输入无效为400, 不存在为404, 有歧义为409, 不一致为422, 其它为500.
+func respond(w http.ResponseWriter, result any, err error) {
+	switch addlib.ErrorCodeOf(err) {
+	case addlib.CodeOK:
+		writeJSON(w, http.StatusOK, result)
+	case addlib.CodeBadInput:
+		writeError(w, http.StatusBadRequest, err)
+	case addlib.CodeNotFound:
+		writeError(w, http.StatusNotFound, err)
+	case addlib.CodeAmbiguous:
+		writeError(w, http.StatusConflict, err)
+	case addlib.CodeInconsistent:
+		writeError(w, http.StatusUnprocessableEntity, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	s, err := newServer("../../lib.add")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.reloadToken = "secret"
+	srv := httptest.NewServer(s.routes())
+	defer srv.Close()
+
+	tests := []struct {
+		method   string
+		path     string
+		token    string
+		body     string
+		status   int
+		expected string
+	}{
+		{"GET", "/healthz", "", "", 200, `{"status":"ok"}`},
+		{"GET", "/cities?province=浙江", "", "", 200, `"杭州市"`},
+		{"GET", "/code?city=杭州&district=西湖", "", "", 200, `{"code":"CN033001012"}`},
+		{"GET", "/code?province=江苏&city=杭州", "", "", 422, `"error"`},
+		{"GET", "/address?city=张家", "", "", 409, `"error"`},
+		{"GET", "/codes/CN033001012", "", "", 200, `"city_code":"CN033001000"`},
+		{"GET", "/codes/foo", "", "", 404, `"error"`},
+		{"GET", "/parse?text=杭州市西湖区文三路", "", "", 200, `"district":"西湖区","municipality":false,"code":"CN033001012","detail":"文三路"`},
+		{"POST", "/batch/code", "", `[{"city":"杭州"},{"city":"foo"}]`, 200,
+			`[{"result":{"code":"CN033001000"}},{"result":null,"code":"not_found","error":"not found, level = city, input = foo"}]`},
+		{"POST", "/batch/parse", "", `["北京市朝阳区"]`, 200, `"municipality":true`},
+		{"POST", "/batch/codes", "", `{"code":"foo"}`, 400, `"error"`},
+		{"GET", "/reload", "", "", 405, `"error"`},
+		{"POST", "/reload", "", "", 401, `"error"`},
+		{"POST", "/reload", "wrong", "", 401, `"error"`},
+		{"POST", "/reload", "secret", "", 200, `"dataset":"` + s.version() + `"`},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body json.RawMessage
+		json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.expected) {
+			t.Errorf("%s %s: expected: %d %s, got: %d %s", tt.method, tt.path, tt.status, tt.expected, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestReloadDisabled(t *testing.T) {
+	s, err := newServer("../../lib.add")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "/reload", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+	s.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected: %d, got: %d", http.StatusForbidden, w.Code)
+	}
+}
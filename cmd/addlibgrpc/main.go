@@ -0,0 +1,50 @@
+//go:build grpc
+
+package main
+
+import (
+	"flag"
+	"goAddLib/addlib"
+	"goAddLib/proto/addlibpb"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+)
+
+//go:generate sh ../../proto/generate.sh
+
+// addlib的gRPC服务. 依赖google.golang.org/grpc和google.golang.org/protobuf, 因此需要用-tags grpc编译:
+// go install -tags grpc goAddLib/cmd/addlibgrpc
+// proto/addlibpb中生成的代码已经提交, 修改addlib.proto之后需要重新生成: cd cmd/addlibgrpc && go generate -tags grpc
+// 收到SIGINT或SIGTERM时等待处理中的请求结束后退出.
+func main() {
+	addr := flag.String("addr", ":9090", "监听地址")
+	dataPath := flag.String("data", "lib.add", "数据文件夹")
+	flag.Parse()
+
+	if err := addlib.Init(*dataPath); err != nil {
+		log.Fatalln("cannot load data:", err)
+	}
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	srv := grpc.NewServer()
+	addlibpb.RegisterAddLibServer(srv, &server{})
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		srv.GracefulStop()
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := srv.Serve(lis); err != nil {
+		log.Fatalln(err)
+	}
+}
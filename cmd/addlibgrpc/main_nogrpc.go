@@ -0,0 +1,14 @@
+//go:build !grpc
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// 没有-tags grpc时只编译与gRPC无关的部分(参考resolve.go), 运行时提示重新编译.
+func main() {
+	fmt.Fprintln(os.Stderr, "addlibgrpc must be built with -tags grpc, see cmd/addlibgrpc/main.go")
+	os.Exit(2)
+}
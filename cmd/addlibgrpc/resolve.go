@@ -0,0 +1,71 @@
+package main
+
+import "goAddLib/addlib"
+
+// 本文件不依赖grpc和生成的代码, 因此不需要-tags grpc就能编译和测试.
+// server.go负责把这里的结果转换为addlibpb的消息和gRPC状态.
+
+// 一条原始地址的解析结果, 对应addlibpb.ResolveResult
+type resolution struct {
+	Address addlib.Address
+	Codes   addlib.AddressCodes
+	Region  addlib.Region // 最具体的一级
+	Detail  string        // 自由文本中剩余的详细地址
+}
+
+// 解析一条原始地址. 若省市区都为空, 则按自由文本解析(参考addlib.Resolve).
+func resolveAddress(province string, city string, district string, text string) (resolution, error) {
+	res, err := addlib.Resolve(province, city, district, text)
+	if err != nil {
+		return resolution{}, err
+	}
+	codes, err := addlib.ParseCode(res.Code)
+	if err != nil {
+		return resolution{}, err
+	}
+	region, _ := addlib.GetRegion(res.Code)
+	return resolution{res.Address, codes, region, res.Detail}, nil
+}
+
+// gRPC状态码, 值与google.golang.org/grpc/codes相同
+type statusCode uint32
+
+const (
+	codeInvalidArgument statusCode = 3
+	codeNotFound        statusCode = 5
+	codeInternal        statusCode = 13
+)
+
+// 查询错误对应的gRPC状态码(参考addlib.ErrorCodeOf): 不存在为NotFound, 输入无效, 有歧义或不一致为InvalidArgument, 其它为Internal.
+func errorStatus(err error) statusCode {
+	switch addlib.ErrorCodeOf(err) {
+	case addlib.CodeNotFound:
+		return codeNotFound
+	case addlib.CodeBadInput, addlib.CodeAmbiguous, addlib.CodeInconsistent:
+		return codeInvalidArgument
+	}
+	return codeInternal
+}
+
+// 查询错误的类型, 值与addlibpb.Error_Code相同
+type errorKind int32
+
+const (
+	kindUnknown errorKind = iota
+	kindNotFound
+	kindAmbiguous
+	kindInconsistent
+)
+
+// 输出查询错误的类型, 用于ResolveResult.error. 没有对应类型的错误码为kindUnknown.
+func errorKindOf(err error) errorKind {
+	switch addlib.ErrorCodeOf(err) {
+	case addlib.CodeNotFound:
+		return kindNotFound
+	case addlib.CodeAmbiguous:
+		return kindAmbiguous
+	case addlib.CodeInconsistent:
+		return kindInconsistent
+	}
+	return kindUnknown
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"goAddLib/addlib"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	if err := addlib.Init("../../lib.add"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestResolveAddress(t *testing.T) {
+	tests := []struct {
+		in       [4]string
+		code     string
+		detail   string
+		expected error
+	}{
+		{[4]string{"", "杭州", "西湖", ""}, "CN033001012", "", nil},
+		{[4]string{"", "", "", "浙江省杭州市西湖区文三路100号"}, "CN033001012", "文三路100号", nil},
+		{[4]string{"浙江", "", "", ""}, "CN033000000", "", nil},
+		{[4]string{"", "foo", "", ""}, "", "", addlib.ErrNotFound},
+		{[4]string{"", "张家", "", ""}, "", "", addlib.ErrAmbiguous},
+		{[4]string{"江苏", "杭州", "", ""}, "", "", addlib.ErrInconsistent},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveAddress(tt.in[0], tt.in[1], tt.in[2], tt.in[3])
+		if !errors.Is(err, tt.expected) {
+			t.Errorf("input: %v, expected: %v, got: %v", tt.in, tt.expected, err)
+			continue
+		}
+		if got.Region.Code != tt.code || got.Detail != tt.detail {
+			t.Errorf("input: %v, expected: %s %s, got: %s %s", tt.in, tt.code, tt.detail, got.Region.Code, got.Detail)
+		}
+	}
+
+	got, _ := resolveAddress("", "杭州", "西湖", "")
+	expected := addlib.AddressCodes{ProvinceCode: "CN033000000", CityCode: "CN033001000", DistrictCode: "CN033001012"}
+	if got.Codes != expected || got.Address.District != "西湖区" {
+		t.Errorf("expected: %v 西湖区, got: %v %s", expected, got.Codes, got.Address.District)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	_, notFound := addlib.LookupCode("", "foo", "")
+	_, ambiguous := addlib.LookupCode("", "张家", "")
+	_, inconsistent := addlib.LookupCode("江苏", "杭州", "")
+	tests := []struct {
+		in     error
+		status statusCode
+		kind   errorKind
+	}{
+		{notFound, codeNotFound, kindNotFound},
+		{ambiguous, codeInvalidArgument, kindAmbiguous},
+		{inconsistent, codeInvalidArgument, kindInconsistent},
+		{addlib.ErrBadInput, codeInvalidArgument, kindUnknown},
+		{&addlib.DataError{Path: "cities.data", Err: addlib.ErrInconsistent}, codeInternal, kindUnknown},
+		{errors.New("foo"), codeInternal, kindUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := errorStatus(tt.in); got != tt.status {
+			t.Errorf("error: %v, expected: %d, got: %d", tt.in, tt.status, got)
+		}
+		if got := errorKindOf(tt.in); got != tt.kind {
+			t.Errorf("error: %v, expected: %d, got: %d", tt.in, tt.kind, got)
+		}
+	}
+}
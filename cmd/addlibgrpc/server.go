@@ -0,0 +1,120 @@
+//go:build grpc
+
+package main
+
+import (
+	"context"
+	"goAddLib/addlib"
+	"goAddLib/proto/addlibpb"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC服务. 地址库只在启动时加载一次, 因此查询不需要加锁.
+type server struct {
+	addlibpb.UnimplementedAddLibServer
+}
+
+func (s *server) GetCode(ctx context.Context, q *addlibpb.AddressQuery) (*addlibpb.CodeReply, error) {
+	code, err := addlib.LookupCode(q.GetProvince(), q.GetCity(), q.GetDistrict())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &addlibpb.CodeReply{Code: code}, nil
+}
+
+func (s *server) ParseAddress(ctx context.Context, q *addlibpb.AddressQuery) (*addlibpb.Address, error) {
+	add, err := addlib.ParseAddress(q.GetProvince(), q.GetCity(), q.GetDistrict())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newAddress(add), nil
+}
+
+func (s *server) ParseCode(ctx context.Context, req *addlibpb.CodeRequest) (*addlibpb.AddressCodes, error) {
+	addc, err := addlib.ParseCode(req.GetCode())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return newAddressCodes(addc), nil
+}
+
+func (s *server) GetRegion(ctx context.Context, req *addlibpb.CodeRequest) (*addlibpb.Region, error) {
+	region, ok := addlib.GetRegion(req.GetCode())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "code not found, code = %s", req.GetCode())
+	}
+	return newRegion(region), nil
+}
+
+// 逐条解析请求流中的原始地址, 按顺序返回结果.
+// 单条记录的错误写在结果中, 只有流本身出错时才返回错误.
+func (s *server) Resolve(stream addlibpb.AddLib_ResolveServer) error {
+	for {
+		raw, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resolve(raw)); err != nil {
+			return err
+		}
+	}
+}
+
+// 解析一条原始地址, 单条记录的错误写在结果中.
+func resolve(raw *addlibpb.RawAddress) *addlibpb.ResolveResult {
+	result := &addlibpb.ResolveResult{Id: raw.GetId()}
+	res, err := resolveAddress(raw.GetProvince(), raw.GetCity(), raw.GetDistrict(), raw.GetText())
+	if err != nil {
+		result.Error = newError(err)
+		return result
+	}
+	result.Address = newAddress(res.Address)
+	result.Codes = newAddressCodes(res.Codes)
+	result.Region = newRegion(res.Region)
+	result.Detail = res.Detail
+	return result
+}
+
+func newAddress(add addlib.Address) *addlibpb.Address {
+	return &addlibpb.Address{
+		Province:     add.Province,
+		City:         add.City,
+		District:     add.District,
+		Municipality: add.Municipality,
+	}
+}
+
+func newAddressCodes(c addlib.AddressCodes) *addlibpb.AddressCodes {
+	return &addlibpb.AddressCodes{
+		ProvinceCode: c.ProvinceCode,
+		CityCode:     c.CityCode,
+		DistrictCode: c.DistrictCode,
+	}
+}
+
+func newRegion(r addlib.Region) *addlibpb.Region {
+	return &addlibpb.Region{
+		Code:       r.Code,
+		Name:       r.Name,
+		ShortName:  r.ShortName,
+		Level:      string(r.Level),
+		Kind:       r.Kind.String(),
+		ParentCode: r.ParentCode,
+		GbCode:     r.GBCode,
+	}
+}
+
+func newError(err error) *addlibpb.Error {
+	return &addlibpb.Error{Code: addlibpb.Error_Code(errorKindOf(err)), Message: err.Error()}
+}
+
+// 把查询错误转换为gRPC状态(参考errorStatus)
+func statusError(err error) error {
+	return status.Error(codes.Code(errorStatus(err)), err.Error())
+}
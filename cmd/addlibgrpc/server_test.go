@@ -0,0 +1,71 @@
+//go:build grpc
+
+package main
+
+import (
+	"context"
+	"goAddLib/proto/addlibpb"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// resolve.go中的常量与gRPC和生成的代码一致
+func TestCodes(t *testing.T) {
+	if codes.Code(codeInvalidArgument) != codes.InvalidArgument || codes.Code(codeNotFound) != codes.NotFound ||
+		codes.Code(codeInternal) != codes.Internal {
+		t.Errorf("status codes do not match google.golang.org/grpc/codes")
+	}
+	if addlibpb.Error_Code(kindNotFound) != addlibpb.Error_NOT_FOUND || addlibpb.Error_Code(kindAmbiguous) != addlibpb.Error_AMBIGUOUS ||
+		addlibpb.Error_Code(kindInconsistent) != addlibpb.Error_INCONSISTENT {
+		t.Errorf("error kinds do not match addlibpb.Error_Code")
+	}
+}
+
+func TestServer(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	addlibpb.RegisterAddLibServer(srv, &server{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	dial := func(context.Context, string) (net.Conn, error) { return lis.Dial() }
+	conn, err := grpc.NewClient("passthrough:///bufnet", grpc.WithContextDialer(dial),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := addlibpb.NewAddLibClient(conn)
+	ctx := context.Background()
+
+	reply, err := client.GetCode(ctx, &addlibpb.AddressQuery{City: "杭州", District: "西湖"})
+	if err != nil || reply.GetCode() != "CN033001012" {
+		t.Errorf("expected: CN033001012, got: %s, %v", reply.GetCode(), err)
+	}
+	_, err = client.GetCode(ctx, &addlibpb.AddressQuery{City: "foo"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected: %s, got: %v", codes.NotFound, err)
+	}
+
+	stream, err := client.Resolve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.Send(&addlibpb.RawAddress{Id: "1", Text: "浙江省杭州市西湖区文三路"})
+	stream.Send(&addlibpb.RawAddress{Id: "2", City: "张家"})
+	stream.CloseSend()
+	first, err := stream.Recv()
+	if err != nil || first.GetId() != "1" || first.GetRegion().GetCode() != "CN033001012" || first.GetDetail() != "文三路" {
+		t.Errorf("unexpected result: %v, %v", first, err)
+	}
+	second, err := stream.Recv()
+	if err != nil || second.GetId() != "2" || second.GetError().GetCode() != addlibpb.Error_AMBIGUOUS {
+		t.Errorf("unexpected result: %v, %v", second, err)
+	}
+}
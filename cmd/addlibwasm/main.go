@@ -0,0 +1,157 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"goAddLib/addlib"
+	"syscall/js"
+)
+
+// 嵌入的地址库快照, 由make生成(参考Makefile)
+//
+//go:embed lib.snapshot
+var snapshot []byte
+
+// 返回给JavaScript的结果: 成功时为{result: ..., error: null}, 失败时为{result: null, error: {code, message}}.
+// 错误码与动态链接库的JSON接口相同(参考addlib.ErrorCodeOf).
+type reply struct {
+	Result any         `json:"result"`
+	Error  *replyError `json:"error"`
+}
+
+type replyError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type address struct {
+	Province     string `json:"province"`
+	City         string `json:"city"`
+	District     string `json:"district"`
+	Municipality bool   `json:"municipality"`
+	Code         string `json:"code"`
+	Detail       string `json:"detail,omitempty"`
+}
+
+type suggestion struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	Level     string `json:"level"`
+	Address   string `json:"address"` // 完整的地址名称, 例如: 浙江省杭州市西湖区
+}
+
+// addlib的WebAssembly入口. 用make编译(参考Makefile), 然后在JavaScript中:
+// const go = new Go(); const { instance } = await WebAssembly.instantiate(bytes, go.importObject); go.run(instance);
+// go.run返回后即可调用globalThis.addlib中的函数: lookup, getName, parse, parseCode和suggest.
+func main() {
+	if err := addlib.ReadSnapshot(bytes.NewReader(snapshot)); err != nil {
+		panic(err)
+	}
+	snapshot = nil
+
+	lib := js.Global().Get("Object").New()
+	lib.Set("lookup", export(lookup))
+	lib.Set("getName", export(getName))
+	lib.Set("parse", export(parse))
+	lib.Set("parseCode", export(parseCode))
+	lib.Set("suggest", export(suggest))
+	js.Global().Set("addlib", lib)
+	select {}
+}
+
+// 把Go函数包装为JavaScript函数, 结果转换为JavaScript对象
+func export(fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) any {
+		result, err := fn(args)
+		r := reply{result, nil}
+		if err != nil {
+			r = reply{nil, &replyError{string(addlib.ErrorCodeOf(err)), err.Error()}}
+		}
+		data, _ := json.Marshal(r)
+		return js.Global().Get("JSON").Call("parse", string(data))
+	})
+}
+
+// 第i个字符串参数. 若没有或者为null, 则返回"".
+func stringArg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].IsUndefined() || args[i].IsNull() {
+		return ""
+	}
+	return args[i].String()
+}
+
+// lookup(province, city, district): 查询标准地址和编码, 规则与addlib.LookupCode相同
+func lookup(args []js.Value) (any, error) {
+	province, city, district := stringArg(args, 0), stringArg(args, 1), stringArg(args, 2)
+	if province == "" && city == "" && district == "" {
+		return nil, addlib.ErrBadInput
+	}
+	res, err := addlib.Resolve(province, city, district, "")
+	if err != nil {
+		return nil, err
+	}
+	return newAddress(res), nil
+}
+
+// getName(code)
+func getName(args []js.Value) (any, error) {
+	code := stringArg(args, 0)
+	if code == "" {
+		return nil, addlib.ErrBadInput
+	}
+	return addlib.LookupName(code)
+}
+
+// parse(text): 解析自由文本, detail为剩余的详细地址
+func parse(args []js.Value) (any, error) {
+	text := stringArg(args, 0)
+	if text == "" {
+		return nil, addlib.ErrBadInput
+	}
+	res, err := addlib.Resolve("", "", "", text)
+	if err != nil {
+		return nil, err
+	}
+	return newAddress(res), nil
+}
+
+// parseCode(code): 输出{province_code, city_code, district_code}
+func parseCode(args []js.Value) (any, error) {
+	code := stringArg(args, 0)
+	if code == "" {
+		return nil, addlib.ErrBadInput
+	}
+	addc, err := addlib.ParseCode(code)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"province_code": addc.ProvinceCode,
+		"city_code":     addc.CityCode,
+		"district_code": addc.DistrictCode,
+	}, nil
+}
+
+// suggest(text, limit): 自动补全, limit默认为10
+func suggest(args []js.Value) (any, error) {
+	limit := 10
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		limit = args[1].Int()
+	}
+	regions := addlib.Suggest(stringArg(args, 0), limit)
+	suggestions := make([]suggestion, len(regions))
+	for i, region := range regions {
+		addc, _ := addlib.ParseCode(region.Code)
+		address, _ := addlib.Format(addc, addlib.StyleFull)
+		suggestions[i] = suggestion{region.Code, region.Name, region.ShortName, string(region.Level), address}
+	}
+	return suggestions, nil
+}
+
+func newAddress(res addlib.Resolution) address {
+	return address{res.Province, res.City, res.District, res.Municipality, res.Code, res.Detail}
+}
@@ -0,0 +1,53 @@
+// addlib.wasm的测试. 运行: make test
+const assert = require("node:assert");
+const fs = require("node:fs");
+const path = require("node:path");
+const test = require("node:test");
+
+require("./wasm_exec.js");
+
+test.before(async () => {
+  const go = new Go();
+  const bytes = fs.readFileSync(path.join(__dirname, "addlib.wasm"));
+  const { instance } = await WebAssembly.instantiate(bytes, go.importObject);
+  go.run(instance);
+});
+
+test("lookup", () => {
+  assert.deepStrictEqual(addlib.lookup("浙江", "杭州", "西湖"), {
+    result: { province: "浙江省", city: "杭州市", district: "西湖区", municipality: false, code: "CN033001012" },
+    error: null,
+  });
+  assert.strictEqual(addlib.lookup("", "杭州").result.code, "CN033001000");
+  assert.strictEqual(addlib.lookup("北京", "", "东城").result.municipality, true);
+});
+
+test("errors", () => {
+  assert.strictEqual(addlib.lookup("", "foo", "").error.code, "not_found");
+  assert.strictEqual(addlib.lookup("江苏", "杭州", "").error.code, "inconsistent");
+  assert.strictEqual(addlib.lookup().error.code, "bad_input");
+  assert.strictEqual(addlib.getName("foo").result, null);
+});
+
+test("getName and parseCode", () => {
+  assert.strictEqual(addlib.getName("CN033001012").result, "西湖区");
+  assert.deepStrictEqual(addlib.parseCode("CN033001012").result, {
+    province_code: "CN033000000",
+    city_code: "CN033001000",
+    district_code: "CN033001012",
+  });
+});
+
+test("parse", () => {
+  const { result } = addlib.parse("浙江省杭州市西湖区文三路100号");
+  assert.strictEqual(result.code, "CN033001012");
+  assert.strictEqual(result.detail, "文三路100号");
+});
+
+test("suggest", () => {
+  assert.deepStrictEqual(addlib.suggest("杭州西").result, [
+    { code: "CN033001012", name: "西湖区", short_name: "西湖", level: "district", address: "浙江省杭州市西湖区" },
+  ]);
+  assert.strictEqual(addlib.suggest("北京东").result[0].address, "北京市东城区");
+  assert.strictEqual(addlib.suggest("", 3).result.length, 3);
+});
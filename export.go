@@ -1,11 +1,33 @@
 package main
 
+/*
+#include <stdlib.h>
+
+// 内存管理:
+// 1. 返回char*的函数都用malloc分配一个新的字符串, 调用方拥有它, 用完后必须调用freeString释放(不要用其它的free, 例如Windows上不同的C运行库).
+// 2. 参数中的字符串由调用方分配和释放, 函数返回后不再引用.
+// 3. freeString(NULL)什么也不做. 同一个字符串不能释放两次.
+*/
 import "C"
 import (
+	"encoding/json"
+	"fmt"
 	"goAddLib/addlib"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
+	"unsafe"
 )
 
+// 释放本库返回的字符串
+//
+//export freeString
+func freeString(str *C.char) {
+	C.free(unsafe.Pointer(str))
+}
+
 //export provinces
 func provinces(mainland bool) *C.char {
 	str := strings.Join(addlib.Provinces(mainland), "\t")
@@ -94,10 +116,695 @@ func initialize(dataPath *C.char) *C.char {
 	return C.CString("")
 }
 
+// 以下为JSON接口. 上面的函数把结果拼接为以tab分隔的字符串, 并且出错时返回"", 调用方无法区分"不存在"和"输入错误".
+// JSON接口的每个函数都返回一个JSON文档(同样需要用freeString释放):
+// 成功时为{"result": ..., "error": null}, 失败时为{"result": null, "error": {"code": ..., "message": ...}}.
+// 错误码: bad_input(输入为空, 不是有效的UTF-8或JSON), not_found, ambiguous, inconsistent(省市区之间不存在管辖关系), data_error(数据文件错误), internal(参考addlib.ErrorCodeOf).
+
+// 批量调用的最大条数
+const maxBatchSize = 10000
+
+type jsonReply struct {
+	Result any        `json:"result"`
+	Error  *jsonError `json:"error"`
+}
+
+type jsonError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonAddress struct {
+	Province     string `json:"province"`
+	City         string `json:"city"`
+	District     string `json:"district"`
+	Municipality bool   `json:"municipality"`
+	Code         string `json:"code"`
+	Detail       string `json:"detail,omitempty"` // 自由文本中剩余的详细地址
+}
+
+type jsonCodes struct {
+	ProvinceCode string `json:"province_code"`
+	CityCode     string `json:"city_code"`
+	DistrictCode string `json:"district_code"`
+}
+
+type jsonRegion struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	ShortName  string `json:"short_name"`
+	Level      string `json:"level"`
+	Kind       string `json:"kind"`
+	ParentCode string `json:"parent_code"`
+	GBCode     string `json:"gb_code"`
+}
+
+// 一种操作: 参数个数和实现. 单个调用和批量调用(batchJSON)共用.
+type jsonOp struct {
+	args int
+	fn   func(args []string) (any, error)
+}
+
+var jsonOps = map[string]jsonOp{
+	"getName": {1, func(args []string) (any, error) {
+		return addlib.LookupName(args[0])
+	}},
+	"getCode": {3, func(args []string) (any, error) {
+		return addlib.LookupCode(args[0], args[1], args[2])
+	}},
+	"getProvinceCode": {1, func(args []string) (any, error) {
+		return addlib.LookupProvinceCode(args[0])
+	}},
+	"getCityCode": {1, func(args []string) (any, error) {
+		return addlib.LookupCityCode(args[0])
+	}},
+	"getDistrictCode": {2, func(args []string) (any, error) {
+		return addlib.LookupDistrictCode(args[0], args[1])
+	}},
+	"parseAddress": {3, func(args []string) (any, error) {
+		add, err := addlib.ParseAddress(args[0], args[1], args[2])
+		if err != nil {
+			return nil, err
+		}
+		return newJSONAddress(add, addlib.GetCode(add.Province, add.City, add.District), ""), nil
+	}},
+	"parseCode": {1, func(args []string) (any, error) {
+		addc, err := addlib.ParseCode(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return jsonCodes{addc.ProvinceCode, addc.CityCode, addc.DistrictCode}, nil
+	}},
+	"parseText": {1, func(args []string) (any, error) {
+		res, err := addlib.Resolve("", "", "", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return newJSONAddress(res.Address, res.Code, res.Detail), nil
+	}},
+	"getRegion": {1, func(args []string) (any, error) {
+		region, ok := addlib.GetRegion(args[0])
+		if !ok {
+			_, err := addlib.LookupName(args[0])
+			return nil, err
+		}
+		return newJSONRegion(region), nil
+	}},
+	"regionPath": {1, func(args []string) (any, error) {
+		path, err := addlib.RegionPath(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return newJSONRegions(path), nil
+	}},
+	"cities": {1, func(args []string) (any, error) {
+		code, err := addlib.LookupProvinceCode(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return names(addlib.CityCodes(code)), nil
+	}},
+	"districts": {1, func(args []string) (any, error) {
+		code, err := addlib.LookupCityCode(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return names(addlib.DistrictCodes(code)), nil
+	}},
+	"cityCodes": {1, func(args []string) (any, error) {
+		return childCodes(args[0], addlib.LevelProvince)
+	}},
+	"districtCodes": {1, func(args []string) (any, error) {
+		return childCodes(args[0], addlib.LevelCity)
+	}},
+	"suggest": {2, func(args []string) (any, error) {
+		limit, err := intArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return newJSONRegions(addlib.Suggest(args[0], limit)), nil
+	}},
+	"format": {3, func(args []string) (any, error) {
+		style, ok := jsonStyles[args[1]]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown style, style = %s", addlib.ErrBadInput, args[1])
+		}
+		addc, err := addlib.ParseCode(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return addlib.FormatDetail(addc, args[2], style)
+	}},
+	"regionSets": {0, func(args []string) (any, error) {
+		sets := addlib.RegionSets()
+		sort.Slice(sets, func(i, j int) bool { return sets[i] < sets[j] })
+		return sets, nil
+	}},
+	"inRegionSet": {2, func(args []string) (any, error) {
+		if err := checkCode(args[0]); err != nil {
+			return nil, err
+		}
+		if err := checkRegionSet(addlib.RegionSet(args[1])); err != nil {
+			return nil, err
+		}
+		return addlib.InRegionSet(args[0], addlib.RegionSet(args[1])), nil
+	}},
+	"provinceCodesIn": {1, func(args []string) (any, error) {
+		if err := checkRegionSet(addlib.RegionSet(args[0])); err != nil {
+			return nil, err
+		}
+		return addlib.ProvinceCodesIn(addlib.RegionSet(args[0])), nil
+	}},
+	"schemes": {0, func(args []string) (any, error) {
+		return addlib.Schemes(), nil
+	}},
+	"groups": {1, func(args []string) (any, error) {
+		if !slices.Contains(addlib.Schemes(), args[0]) {
+			return nil, fmt.Errorf("%w, scheme = %s", addlib.ErrNotFound, args[0])
+		}
+		return addlib.Groups(args[0]), nil
+	}},
+	"members": {2, func(args []string) (any, error) {
+		if !slices.Contains(addlib.Groups(args[0]), args[1]) {
+			return nil, fmt.Errorf("%w, scheme = %s, group = %s", addlib.ErrNotFound, args[0], args[1])
+		}
+		return addlib.Members(args[0], args[1]), nil
+	}},
+	"regionGroups": {1, func(args []string) (any, error) {
+		if err := checkCode(args[0]); err != nil {
+			return nil, err
+		}
+		return addlib.RegionGroups(args[0]), nil
+	}},
+	"parent": {1, func(args []string) (any, error) {
+		if err := checkCode(args[0]); err != nil {
+			return nil, err
+		}
+		return addlib.Parent(args[0]), nil
+	}},
+	"ancestors": {1, func(args []string) (any, error) {
+		if err := checkCode(args[0]); err != nil {
+			return nil, err
+		}
+		return addlib.Ancestors(args[0]), nil
+	}},
+	"siblings": {1, func(args []string) (any, error) {
+		if err := checkCode(args[0]); err != nil {
+			return nil, err
+		}
+		return addlib.Siblings(args[0]), nil
+	}},
+	"descendants": {2, func(args []string) (any, error) {
+		maxDepth, err := intArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		// ROOT表示整棵地址树
+		if args[0] != addlib.ROOT {
+			if err := checkCode(args[0]); err != nil {
+				return nil, err
+			}
+		}
+		return addlib.Descendants(args[0], maxDepth), nil
+	}},
+}
+
+// format的样式名称
+var jsonStyles = map[string]addlib.Style{
+	"full":             addlib.StyleFull,
+	"short":            addlib.StyleShort,
+	"province_english": addlib.StyleProvinceEnglish,
+	"province_pinyin":  addlib.StyleProvincePinyin,
+}
+
+// 执行一种操作. 若参数都为""(没有参数的操作除外)或者不是有效的UTF-8, 则返回addlib.ErrBadInput.
+func callOp(op string, args []string) (any, error) {
+	jop, ok := jsonOps[op]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown op, op = %s", addlib.ErrBadInput, op)
+	}
+	if len(args) != jop.args {
+		return nil, fmt.Errorf("%w: wrong number of arguments, op = %s, expected: %d, got: %d", addlib.ErrBadInput, op, jop.args, len(args))
+	}
+	empty := true
+	for _, arg := range args {
+		if !utf8.ValidString(arg) {
+			return nil, fmt.Errorf("%w: invalid UTF-8", addlib.ErrBadInput)
+		}
+		if arg != "" {
+			empty = false
+		}
+	}
+	if empty && len(args) > 0 {
+		return nil, fmt.Errorf("%w: empty input", addlib.ErrBadInput)
+	}
+	return jop.fn(args)
+}
+
+// 执行一种操作, 输出JSON文档
+func callJSON(op string, args ...*C.char) *C.char {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = C.GoString(arg)
+	}
+	return replyJSON(callOp(op, values))
+}
+
+func replyJSON(result any, err error) *C.char {
+	data, err := json.Marshal(newJSONReply(result, err))
+	if err != nil {
+		data, _ = json.Marshal(newJSONReply(nil, err))
+	}
+	return C.CString(string(data))
+}
+
+func newJSONReply(result any, err error) jsonReply {
+	if err == nil {
+		return jsonReply{result, nil}
+	}
+	return jsonReply{nil, &jsonError{string(addlib.ErrorCodeOf(err)), err.Error()}}
+}
+
+func newJSONAddress(add addlib.Address, code string, detail string) jsonAddress {
+	return jsonAddress{add.Province, add.City, add.District, add.Municipality, code, detail}
+}
+
+func newJSONRegion(region addlib.Region) jsonRegion {
+	return jsonRegion{region.Code, region.Name, region.ShortName, string(region.Level), region.Kind.String(),
+		region.ParentCode, region.GBCode}
+}
+
+func newJSONRegions(regions []addlib.Region) []jsonRegion {
+	result := make([]jsonRegion, len(regions))
+	for i, region := range regions {
+		result[i] = newJSONRegion(region)
+	}
+	return result
+}
+
+// 解析整数参数, ""为0. 若不是整数, 则返回addlib.ErrBadInput.
+func intArg(arg string) (int, error) {
+	if arg == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("%w: expected an integer, got: %s", addlib.ErrBadInput, arg)
+	}
+	return n, nil
+}
+
+// 若编码不存在, 则返回ErrNotFound
+func checkCode(code string) error {
+	_, err := addlib.LookupName(code)
+	return err
+}
+
+// 若区域集合未定义, 则返回ErrNotFound
+func checkRegionSet(set addlib.RegionSet) error {
+	if set != addlib.SetAll && !slices.Contains(addlib.RegionSets(), set) {
+		return fmt.Errorf("%w, set = %s", addlib.ErrNotFound, set)
+	}
+	return nil
+}
+
+func names(codes []string) []string {
+	names := make([]string, len(codes))
+	for i, code := range codes {
+		names[i] = addlib.GetName(code)
+	}
+	return names
+}
+
+// 输出下一级的编码. 若code不存在, 则返回ErrNotFound; 若code不是level级, 则返回addlib.ErrBadInput.
+func childCodes(code string, level addlib.Level) ([]string, error) {
+	if _, err := addlib.LookupName(code); err != nil {
+		return nil, err
+	}
+	if addlib.GetLevel(code) != level {
+		return nil, fmt.Errorf("%w: expected a %s code, got: %s", addlib.ErrBadInput, level, code)
+	}
+	if level == addlib.LevelProvince {
+		return addlib.CityCodes(code), nil
+	}
+	return addlib.DistrictCodes(code), nil
+}
+
+// 初始化地址库. result为区划的个数
+//
+//export initializeJSON
+func initializeJSON(dataPath *C.char) *C.char {
+	if err := addlib.Init(C.GoString(dataPath)); err != nil {
+		return replyJSON(nil, err)
+	}
+	return replyJSON(map[string]int{"regions": len(addlib.Descendants(addlib.ROOT, 0))}, nil)
+}
+
+// result为省名的数组
+//
+//export provincesJSON
+func provincesJSON(mainland bool) *C.char {
+	return replyJSON(addlib.Provinces(mainland), nil)
+}
+
+// result为省编码的数组
+//
+//export provinceCodesJSON
+func provinceCodesJSON(mainland bool) *C.char {
+	return replyJSON(addlib.ProvinceCodes(mainland), nil)
+}
+
+// result为市名的数组
+//
+//export citiesJSON
+func citiesJSON(ofProvince *C.char) *C.char {
+	return callJSON("cities", ofProvince)
+}
+
+// result为区名的数组
+//
+//export districtsJSON
+func districtsJSON(ofCity *C.char) *C.char {
+	return callJSON("districts", ofCity)
+}
+
+// result为市编码的数组
+//
+//export cityCodesJSON
+func cityCodesJSON(ofProvinceCode *C.char) *C.char {
+	return callJSON("cityCodes", ofProvinceCode)
+}
+
+// result为区编码的数组
+//
+//export districtCodesJSON
+func districtCodesJSON(ofCityCode *C.char) *C.char {
+	return callJSON("districtCodes", ofCityCode)
+}
+
+// result为名称
+//
+//export getNameJSON
+func getNameJSON(code *C.char) *C.char {
+	return callJSON("getName", code)
+}
+
+// result为编码
+//
+//export getCodeJSON
+func getCodeJSON(provinceName *C.char, cityName *C.char, districtName *C.char) *C.char {
+	return callJSON("getCode", provinceName, cityName, districtName)
+}
+
+// result为编码
+//
+//export getProvinceCodeJSON
+func getProvinceCodeJSON(provinceName *C.char) *C.char {
+	return callJSON("getProvinceCode", provinceName)
+}
+
+// result为编码
+//
+//export getCityCodeJSON
+func getCityCodeJSON(cityName *C.char) *C.char {
+	return callJSON("getCityCode", cityName)
+}
+
+// result为编码
+//
+//export getDistrictCodeJSON
+func getDistrictCodeJSON(cityName *C.char, districtName *C.char) *C.char {
+	return callJSON("getDistrictCode", cityName, districtName)
+}
+
+// result为{"province", "city", "district", "municipality", "code"}
+//
+//export parseAddressJSON
+func parseAddressJSON(provinceName *C.char, cityName *C.char, districtName *C.char) *C.char {
+	return callJSON("parseAddress", provinceName, cityName, districtName)
+}
+
+// result为{"province_code", "city_code", "district_code"}
+//
+//export parseCodeJSON
+func parseCodeJSON(code *C.char) *C.char {
+	return callJSON("parseCode", code)
+}
+
+// result与parseAddressJSON相同, 另外detail为剩余的详细地址
+//
+//export parseTextJSON
+func parseTextJSON(text *C.char) *C.char {
+	return callJSON("parseText", text)
+}
+
+// result为{"code", "name", "short_name", "level", "kind", "parent_code", "gb_code"}
+//
+//export getRegionJSON
+func getRegionJSON(code *C.char) *C.char {
+	return callJSON("getRegion", code)
+}
+
+// result为从省到code的区划数组
+//
+//export regionPathJSON
+func regionPathJSON(code *C.char) *C.char {
+	return callJSON("regionPath", code)
+}
+
+// text为未完成的地址, limit <= 0表示不限制个数. result为候选区划的数组(参考getRegionJSON)
+//
+//export suggestJSON
+func suggestJSON(text *C.char, limit C.int) *C.char {
+	return replyJSON(callOp("suggest", []string{C.GoString(text), strconv.Itoa(int(limit))}))
+}
+
+// style为full, short, province_english或province_pinyin(只翻译省级名称), detail为详细地址(可以为""). result为格式化的地址
+//
+//export formatJSON
+func formatJSON(code *C.char, style *C.char, detail *C.char) *C.char {
+	return callJSON("format", code, style, detail)
+}
+
+// result为已定义的区域集合名称的数组(不包括all)
+//
+//export regionSetsJSON
+func regionSetsJSON() *C.char {
+	return callJSON("regionSets")
+}
+
+// result为true或false
+//
+//export inRegionSetJSON
+func inRegionSetJSON(code *C.char, set *C.char) *C.char {
+	return callJSON("inRegionSet", code, set)
+}
+
+// result为区域集合中的省编码的数组
+//
+//export provinceCodesInJSON
+func provinceCodesInJSON(set *C.char) *C.char {
+	return callJSON("provinceCodesIn", set)
+}
+
+// result为分组方案名称的数组
+//
+//export schemesJSON
+func schemesJSON() *C.char {
+	return callJSON("schemes")
+}
+
+// result为方案中的分组名称的数组
+//
+//export groupsJSON
+func groupsJSON(scheme *C.char) *C.char {
+	return callJSON("groups", scheme)
+}
+
+// result为分组的成员编码的数组
+//
+//export membersJSON
+func membersJSON(scheme *C.char, group *C.char) *C.char {
+	return callJSON("members", scheme, group)
+}
+
+// result为{方案名称: [分组名称]}
+//
+//export regionGroupsJSON
+func regionGroupsJSON(code *C.char) *C.char {
+	return callJSON("regionGroups", code)
+}
+
+// result为父节点的编码, 省的父节点为""
+//
+//export parentJSON
+func parentJSON(code *C.char) *C.char {
+	return callJSON("parent", code)
+}
+
+// result为祖先编码的数组(由近及远)
+//
+//export ancestorsJSON
+func ancestorsJSON(code *C.char) *C.char {
+	return callJSON("ancestors", code)
+}
+
+// result为兄弟节点编码的数组(不包括自身)
+//
+//export siblingsJSON
+func siblingsJSON(code *C.char) *C.char {
+	return callJSON("siblings", code)
+}
+
+// result为按深度优先排列的后代编码的数组, maxDepth <= 0表示不限制深度
+//
+//export descendantsJSON
+func descendantsJSON(code *C.char, maxDepth C.int) *C.char {
+	return replyJSON(callOp("descendants", []string{C.GoString(code), strconv.Itoa(int(maxDepth))}))
+}
+
+// 批量调用. op为上面的函数名去掉JSON后缀, 例如"getCode"; items为参数数组的JSON数组, 例如[["浙江", "杭州", "西湖"], ["", "杭州", ""]].
+// 只有一个参数时也可以直接写字符串, 例如["CN033001012", "CN033001000"].
+// result为与items一一对应的{"result", "error"}数组. 只有请求本身无效(例如items不是JSON数组, 超过10000条)时才返回error.
+//
+//export batchJSON
+func batchJSON(op *C.char, items *C.char) *C.char {
+	name := C.GoString(op)
+	if _, ok := jsonOps[name]; !ok {
+		return replyJSON(nil, fmt.Errorf("%w: unknown op, op = %s", addlib.ErrBadInput, name))
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(C.GoString(items)), &raw); err != nil {
+		return replyJSON(nil, fmt.Errorf("%w: %v", addlib.ErrBadInput, err))
+	}
+	if len(raw) > maxBatchSize {
+		return replyJSON(nil, fmt.Errorf("%w: too many items in batch, max = %d", addlib.ErrBadInput, maxBatchSize))
+	}
+	replies := make([]jsonReply, len(raw))
+	for i, item := range raw {
+		var args []string
+		if err := json.Unmarshal(item, &args); err != nil {
+			var arg string
+			if json.Unmarshal(item, &arg) != nil {
+				replies[i] = newJSONReply(nil, fmt.Errorf("%w: item must be a string or an array of strings", addlib.ErrBadInput))
+				continue
+			}
+			args = []string{arg}
+		}
+		replies[i] = newJSONReply(callOp(name, args))
+	}
+	return replyJSON(replies, nil)
+}
+
+// 以下为按行批量调用的接口, 一次处理一整列数据(例如Python中的向量化计算), 避免每一行都跨越cgo调用.
+// 输入为以"\n"分隔的多行(最后的"\n"可以省略, 行尾的"\r"忽略), 每行的字段以"\t"分隔, 缺少的字段为"".
+// 输出的行与输入的行一一对应, 每行以"\n"结尾, 最后一个字段为错误码(成功时为"", 参考JSON接口). 失败的行其它字段都为"".
+
+// 按行执行一种操作. fields为输入的字段数, 若为1, 则整行作为一个字段. format把结果转换为输出的字段.
+func batchLines(input *C.char, op string, fields int, outFields int, format func(result any) []string) *C.char {
+	var b strings.Builder
+	for _, line := range splitLines(C.GoString(input)) {
+		args := []string{line}
+		if fields > 1 {
+			args = strings.Split(line, "\t")
+		}
+		var values []string
+		var err error
+		if len(args) > fields {
+			err = fmt.Errorf("%w: too many fields, expected: %d, got: %d", addlib.ErrBadInput, fields, len(args))
+		} else {
+			args = append(args, make([]string, fields-len(args))...)
+			var result any
+			if result, err = callOp(op, args); err == nil {
+				values = format(result)
+			}
+		}
+		if err != nil {
+			values = make([]string, outFields)
+		}
+		for _, value := range values {
+			b.WriteString(value)
+			b.WriteByte('\t')
+		}
+		b.WriteString(string(addlib.ErrorCodeOf(err)))
+		b.WriteByte('\n')
+	}
+	return C.CString(b.String())
+}
+
+func splitLines(input string) []string {
+	if input == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(input, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+func formatString(result any) []string {
+	return []string{result.(string)}
+}
+
+// 输出的字段: 省, 市, 区, 是否为直辖市(1或0), 编码, 以及详细地址(可选). 详细地址中的"\t"替换为空格.
+func formatAddress(detail bool) func(result any) []string {
+	return func(result any) []string {
+		add := result.(jsonAddress)
+		municipality := "0"
+		if add.Municipality {
+			municipality = "1"
+		}
+		values := []string{add.Province, add.City, add.District, municipality, add.Code}
+		if detail {
+			values = append(values, strings.ReplaceAll(add.Detail, "\t", " "))
+		}
+		return values
+	}
+}
+
+// 批量查询编码. 每行为"省\t市\t区", 输出"编码\t错误码"
+//
+//export getCodes
+func getCodes(lines *C.char) *C.char {
+	return batchLines(lines, "getCode", 3, 1, formatString)
+}
+
+// 批量查询名称. 每行为编码, 输出"名称\t错误码"
+//
+//export getNames
+func getNames(lines *C.char) *C.char {
+	return batchLines(lines, "getName", 1, 1, formatString)
+}
+
+// 批量解析地址. 每行为"省\t市\t区", 输出"省\t市\t区\t直辖市\t编码\t错误码"
+//
+//export parseAddresses
+func parseAddresses(lines *C.char) *C.char {
+	return batchLines(lines, "parseAddress", 3, 5, formatAddress(false))
+}
+
+// 批量解析编码. 每行为编码, 输出"省编码\t市编码\t区编码\t错误码"
+//
+//export parseCodes
+func parseCodes(lines *C.char) *C.char {
+	return batchLines(lines, "parseCode", 1, 3, func(result any) []string {
+		addc := result.(jsonCodes)
+		return []string{addc.ProvinceCode, addc.CityCode, addc.DistrictCode}
+	})
+}
+
+// 批量解析自由文本. 每行为地址文本, 输出"省\t市\t区\t直辖市\t编码\t详细地址\t错误码"
+//
+//export parseTexts
+func parseTexts(lines *C.char) *C.char {
+	return batchLines(lines, "parseText", 1, 6, formatAddress(true))
+}
 
 /**
- 进入goAddLib目录, 然后用如下命令生成动态链接库:
+ 进入goAddLib目录, 然后用如下命令生成动态链接库和头文件addlib.h:
  go build -buildmode=c-shared -o addlib.so ./export.go
+ C的调用示例见c/example.c
  */
 func main() {
 
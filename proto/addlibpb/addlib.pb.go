@@ -0,0 +1,798 @@
+//go:build grpc
+
+// addlib的gRPC接口.
+// 生成的Go代码在addlibpb中(带grpc构建标签), 修改本文件之后需要重新生成并提交:
+// proto/generate.sh(需要protoc, protoc-gen-go和protoc-gen-go-grpc)
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: addlib.proto
+
+package addlibpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Error_Code int32
+
+const (
+	Error_UNKNOWN      Error_Code = 0
+	Error_NOT_FOUND    Error_Code = 1
+	Error_AMBIGUOUS    Error_Code = 2
+	Error_INCONSISTENT Error_Code = 3
+)
+
+// Enum value maps for Error_Code.
+var (
+	Error_Code_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "NOT_FOUND",
+		2: "AMBIGUOUS",
+		3: "INCONSISTENT",
+	}
+	Error_Code_value = map[string]int32{
+		"UNKNOWN":      0,
+		"NOT_FOUND":    1,
+		"AMBIGUOUS":    2,
+		"INCONSISTENT": 3,
+	}
+)
+
+func (x Error_Code) Enum() *Error_Code {
+	p := new(Error_Code)
+	*p = x
+	return p
+}
+
+func (x Error_Code) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Error_Code) Descriptor() protoreflect.EnumDescriptor {
+	return file_addlib_proto_enumTypes[0].Descriptor()
+}
+
+func (Error_Code) Type() protoreflect.EnumType {
+	return &file_addlib_proto_enumTypes[0]
+}
+
+func (x Error_Code) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Error_Code.Descriptor instead.
+func (Error_Code) EnumDescriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type AddressQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Province      string                 `protobuf:"bytes,1,opt,name=province,proto3" json:"province,omitempty"`
+	City          string                 `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	District      string                 `protobuf:"bytes,3,opt,name=district,proto3" json:"district,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressQuery) Reset() {
+	*x = AddressQuery{}
+	mi := &file_addlib_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressQuery) ProtoMessage() {}
+
+func (x *AddressQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressQuery.ProtoReflect.Descriptor instead.
+func (*AddressQuery) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddressQuery) GetProvince() string {
+	if x != nil {
+		return x.Province
+	}
+	return ""
+}
+
+func (x *AddressQuery) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *AddressQuery) GetDistrict() string {
+	if x != nil {
+		return x.District
+	}
+	return ""
+}
+
+type CodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CodeRequest) Reset() {
+	*x = CodeRequest{}
+	mi := &file_addlib_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CodeRequest) ProtoMessage() {}
+
+func (x *CodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CodeRequest.ProtoReflect.Descriptor instead.
+func (*CodeRequest) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type CodeReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CodeReply) Reset() {
+	*x = CodeReply{}
+	mi := &file_addlib_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CodeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CodeReply) ProtoMessage() {}
+
+func (x *CodeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CodeReply.ProtoReflect.Descriptor instead.
+func (*CodeReply) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CodeReply) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// 标准三级地址. 直辖市的省名和市名相同.
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Province      string                 `protobuf:"bytes,1,opt,name=province,proto3" json:"province,omitempty"`
+	City          string                 `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	District      string                 `protobuf:"bytes,3,opt,name=district,proto3" json:"district,omitempty"`
+	Municipality  bool                   `protobuf:"varint,4,opt,name=municipality,proto3" json:"municipality,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_addlib_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Address) GetProvince() string {
+	if x != nil {
+		return x.Province
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetDistrict() string {
+	if x != nil {
+		return x.District
+	}
+	return ""
+}
+
+func (x *Address) GetMunicipality() bool {
+	if x != nil {
+		return x.Municipality
+	}
+	return false
+}
+
+type AddressCodes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProvinceCode  string                 `protobuf:"bytes,1,opt,name=province_code,json=provinceCode,proto3" json:"province_code,omitempty"`
+	CityCode      string                 `protobuf:"bytes,2,opt,name=city_code,json=cityCode,proto3" json:"city_code,omitempty"`
+	DistrictCode  string                 `protobuf:"bytes,3,opt,name=district_code,json=districtCode,proto3" json:"district_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressCodes) Reset() {
+	*x = AddressCodes{}
+	mi := &file_addlib_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressCodes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressCodes) ProtoMessage() {}
+
+func (x *AddressCodes) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressCodes.ProtoReflect.Descriptor instead.
+func (*AddressCodes) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AddressCodes) GetProvinceCode() string {
+	if x != nil {
+		return x.ProvinceCode
+	}
+	return ""
+}
+
+func (x *AddressCodes) GetCityCode() string {
+	if x != nil {
+		return x.CityCode
+	}
+	return ""
+}
+
+func (x *AddressCodes) GetDistrictCode() string {
+	if x != nil {
+		return x.DistrictCode
+	}
+	return ""
+}
+
+// 地址区划, 与addlib.Region相同
+type Region struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ShortName     string                 `protobuf:"bytes,3,opt,name=short_name,json=shortName,proto3" json:"short_name,omitempty"`
+	Level         string                 `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`                             // province, city或district
+	Kind          string                 `protobuf:"bytes,5,opt,name=kind,proto3" json:"kind,omitempty"`                               // 行政区划类型, 例如: 地级市
+	ParentCode    string                 `protobuf:"bytes,6,opt,name=parent_code,json=parentCode,proto3" json:"parent_code,omitempty"` // 省的父节点编码为""
+	GbCode        string                 `protobuf:"bytes,7,opt,name=gb_code,json=gbCode,proto3" json:"gb_code,omitempty"`             // 国家标准代码, 若数据中没有则为""
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Region) Reset() {
+	*x = Region{}
+	mi := &file_addlib_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Region) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Region) ProtoMessage() {}
+
+func (x *Region) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Region.ProtoReflect.Descriptor instead.
+func (*Region) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Region) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Region) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Region) GetShortName() string {
+	if x != nil {
+		return x.ShortName
+	}
+	return ""
+}
+
+func (x *Region) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *Region) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Region) GetParentCode() string {
+	if x != nil {
+		return x.ParentCode
+	}
+	return ""
+}
+
+func (x *Region) GetGbCode() string {
+	if x != nil {
+		return x.GbCode
+	}
+	return ""
+}
+
+// 原始地址记录. 若province, city, district都为空, 则按自由文本解析text.
+type RawAddress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // 调用方的记录标识, 原样返回
+	Province      string                 `protobuf:"bytes,2,opt,name=province,proto3" json:"province,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	District      string                 `protobuf:"bytes,4,opt,name=district,proto3" json:"district,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RawAddress) Reset() {
+	*x = RawAddress{}
+	mi := &file_addlib_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RawAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RawAddress) ProtoMessage() {}
+
+func (x *RawAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RawAddress.ProtoReflect.Descriptor instead.
+func (*RawAddress) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RawAddress) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RawAddress) GetProvince() string {
+	if x != nil {
+		return x.Province
+	}
+	return ""
+}
+
+func (x *RawAddress) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *RawAddress) GetDistrict() string {
+	if x != nil {
+		return x.District
+	}
+	return ""
+}
+
+func (x *RawAddress) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ResolveResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Address       *Address               `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Codes         *AddressCodes          `protobuf:"bytes,3,opt,name=codes,proto3" json:"codes,omitempty"`
+	Region        *Region                `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"` // 最具体的一级
+	Detail        string                 `protobuf:"bytes,5,opt,name=detail,proto3" json:"detail,omitempty"` // 自由文本中剩余的详细地址
+	Error         *Error                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`   // 成功时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveResult) Reset() {
+	*x = ResolveResult{}
+	mi := &file_addlib_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveResult) ProtoMessage() {}
+
+func (x *ResolveResult) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveResult.ProtoReflect.Descriptor instead.
+func (*ResolveResult) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResolveResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ResolveResult) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ResolveResult) GetCodes() *AddressCodes {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+func (x *ResolveResult) GetRegion() *Region {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+func (x *ResolveResult) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *ResolveResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          Error_Code             `protobuf:"varint,1,opt,name=code,proto3,enum=addlib.v1.Error_Code" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_addlib_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_addlib_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_addlib_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Error) GetCode() Error_Code {
+	if x != nil {
+		return x.Code
+	}
+	return Error_UNKNOWN
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_addlib_proto protoreflect.FileDescriptor
+
+const file_addlib_proto_rawDesc = "" +
+	"\n" +
+	"\faddlib.proto\x12\taddlib.v1\"Z\n" +
+	"\fAddressQuery\x12\x1a\n" +
+	"\bprovince\x18\x01 \x01(\tR\bprovince\x12\x12\n" +
+	"\x04city\x18\x02 \x01(\tR\x04city\x12\x1a\n" +
+	"\bdistrict\x18\x03 \x01(\tR\bdistrict\"!\n" +
+	"\vCodeRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x1f\n" +
+	"\tCodeReply\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"y\n" +
+	"\aAddress\x12\x1a\n" +
+	"\bprovince\x18\x01 \x01(\tR\bprovince\x12\x12\n" +
+	"\x04city\x18\x02 \x01(\tR\x04city\x12\x1a\n" +
+	"\bdistrict\x18\x03 \x01(\tR\bdistrict\x12\"\n" +
+	"\fmunicipality\x18\x04 \x01(\bR\fmunicipality\"u\n" +
+	"\fAddressCodes\x12#\n" +
+	"\rprovince_code\x18\x01 \x01(\tR\fprovinceCode\x12\x1b\n" +
+	"\tcity_code\x18\x02 \x01(\tR\bcityCode\x12#\n" +
+	"\rdistrict_code\x18\x03 \x01(\tR\fdistrictCode\"\xb3\x01\n" +
+	"\x06Region\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"short_name\x18\x03 \x01(\tR\tshortName\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\tR\x05level\x12\x12\n" +
+	"\x04kind\x18\x05 \x01(\tR\x04kind\x12\x1f\n" +
+	"\vparent_code\x18\x06 \x01(\tR\n" +
+	"parentCode\x12\x17\n" +
+	"\agb_code\x18\a \x01(\tR\x06gbCode\"|\n" +
+	"\n" +
+	"RawAddress\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bprovince\x18\x02 \x01(\tR\bprovince\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x1a\n" +
+	"\bdistrict\x18\x04 \x01(\tR\bdistrict\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\"\xe7\x01\n" +
+	"\rResolveResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12,\n" +
+	"\aaddress\x18\x02 \x01(\v2\x12.addlib.v1.AddressR\aaddress\x12-\n" +
+	"\x05codes\x18\x03 \x01(\v2\x17.addlib.v1.AddressCodesR\x05codes\x12)\n" +
+	"\x06region\x18\x04 \x01(\v2\x11.addlib.v1.RegionR\x06region\x12\x16\n" +
+	"\x06detail\x18\x05 \x01(\tR\x06detail\x12&\n" +
+	"\x05error\x18\x06 \x01(\v2\x10.addlib.v1.ErrorR\x05error\"\x91\x01\n" +
+	"\x05Error\x12)\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x15.addlib.v1.Error.CodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"C\n" +
+	"\x04Code\x12\v\n" +
+	"\aUNKNOWN\x10\x00\x12\r\n" +
+	"\tNOT_FOUND\x10\x01\x12\r\n" +
+	"\tAMBIGUOUS\x10\x02\x12\x10\n" +
+	"\fINCONSISTENT\x10\x032\xb5\x02\n" +
+	"\x06AddLib\x128\n" +
+	"\aGetCode\x12\x17.addlib.v1.AddressQuery\x1a\x14.addlib.v1.CodeReply\x12;\n" +
+	"\fParseAddress\x12\x17.addlib.v1.AddressQuery\x1a\x12.addlib.v1.Address\x12<\n" +
+	"\tParseCode\x12\x16.addlib.v1.CodeRequest\x1a\x17.addlib.v1.AddressCodes\x126\n" +
+	"\tGetRegion\x12\x16.addlib.v1.CodeRequest\x1a\x11.addlib.v1.Region\x12>\n" +
+	"\aResolve\x12\x15.addlib.v1.RawAddress\x1a\x18.addlib.v1.ResolveResult(\x010\x01B\x19Z\x17goAddLib/proto/addlibpbb\x06proto3"
+
+var (
+	file_addlib_proto_rawDescOnce sync.Once
+	file_addlib_proto_rawDescData []byte
+)
+
+func file_addlib_proto_rawDescGZIP() []byte {
+	file_addlib_proto_rawDescOnce.Do(func() {
+		file_addlib_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_addlib_proto_rawDesc), len(file_addlib_proto_rawDesc)))
+	})
+	return file_addlib_proto_rawDescData
+}
+
+var file_addlib_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_addlib_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_addlib_proto_goTypes = []any{
+	(Error_Code)(0),       // 0: addlib.v1.Error.Code
+	(*AddressQuery)(nil),  // 1: addlib.v1.AddressQuery
+	(*CodeRequest)(nil),   // 2: addlib.v1.CodeRequest
+	(*CodeReply)(nil),     // 3: addlib.v1.CodeReply
+	(*Address)(nil),       // 4: addlib.v1.Address
+	(*AddressCodes)(nil),  // 5: addlib.v1.AddressCodes
+	(*Region)(nil),        // 6: addlib.v1.Region
+	(*RawAddress)(nil),    // 7: addlib.v1.RawAddress
+	(*ResolveResult)(nil), // 8: addlib.v1.ResolveResult
+	(*Error)(nil),         // 9: addlib.v1.Error
+}
+var file_addlib_proto_depIdxs = []int32{
+	4,  // 0: addlib.v1.ResolveResult.address:type_name -> addlib.v1.Address
+	5,  // 1: addlib.v1.ResolveResult.codes:type_name -> addlib.v1.AddressCodes
+	6,  // 2: addlib.v1.ResolveResult.region:type_name -> addlib.v1.Region
+	9,  // 3: addlib.v1.ResolveResult.error:type_name -> addlib.v1.Error
+	0,  // 4: addlib.v1.Error.code:type_name -> addlib.v1.Error.Code
+	1,  // 5: addlib.v1.AddLib.GetCode:input_type -> addlib.v1.AddressQuery
+	1,  // 6: addlib.v1.AddLib.ParseAddress:input_type -> addlib.v1.AddressQuery
+	2,  // 7: addlib.v1.AddLib.ParseCode:input_type -> addlib.v1.CodeRequest
+	2,  // 8: addlib.v1.AddLib.GetRegion:input_type -> addlib.v1.CodeRequest
+	7,  // 9: addlib.v1.AddLib.Resolve:input_type -> addlib.v1.RawAddress
+	3,  // 10: addlib.v1.AddLib.GetCode:output_type -> addlib.v1.CodeReply
+	4,  // 11: addlib.v1.AddLib.ParseAddress:output_type -> addlib.v1.Address
+	5,  // 12: addlib.v1.AddLib.ParseCode:output_type -> addlib.v1.AddressCodes
+	6,  // 13: addlib.v1.AddLib.GetRegion:output_type -> addlib.v1.Region
+	8,  // 14: addlib.v1.AddLib.Resolve:output_type -> addlib.v1.ResolveResult
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_addlib_proto_init() }
+func file_addlib_proto_init() {
+	if File_addlib_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_addlib_proto_rawDesc), len(file_addlib_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_addlib_proto_goTypes,
+		DependencyIndexes: file_addlib_proto_depIdxs,
+		EnumInfos:         file_addlib_proto_enumTypes,
+		MessageInfos:      file_addlib_proto_msgTypes,
+	}.Build()
+	File_addlib_proto = out.File
+	file_addlib_proto_goTypes = nil
+	file_addlib_proto_depIdxs = nil
+}
@@ -0,0 +1,286 @@
+//go:build grpc
+
+// addlib的gRPC接口.
+// 生成的Go代码在addlibpb中(带grpc构建标签), 修改本文件之后需要重新生成并提交:
+// proto/generate.sh(需要protoc, protoc-gen-go和protoc-gen-go-grpc)
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: addlib.proto
+
+package addlibpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AddLib_GetCode_FullMethodName      = "/addlib.v1.AddLib/GetCode"
+	AddLib_ParseAddress_FullMethodName = "/addlib.v1.AddLib/ParseAddress"
+	AddLib_ParseCode_FullMethodName    = "/addlib.v1.AddLib/ParseCode"
+	AddLib_GetRegion_FullMethodName    = "/addlib.v1.AddLib/GetRegion"
+	AddLib_Resolve_FullMethodName      = "/addlib.v1.AddLib/Resolve"
+)
+
+// AddLibClient is the client API for AddLib service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AddLibClient interface {
+	// 通过地址名称查询编码, 规则与LookupCode相同
+	GetCode(ctx context.Context, in *AddressQuery, opts ...grpc.CallOption) (*CodeReply, error)
+	// 解析省市区名称, 规则与ParseAddress相同
+	ParseAddress(ctx context.Context, in *AddressQuery, opts ...grpc.CallOption) (*Address, error)
+	// 输入地址编码, 输出其所属省市区编码
+	ParseCode(ctx context.Context, in *CodeRequest, opts ...grpc.CallOption) (*AddressCodes, error)
+	// 通过编码查询地址区划
+	GetRegion(ctx context.Context, in *CodeRequest, opts ...grpc.CallOption) (*Region, error)
+	// 批量解析原始地址. 每收到一条记录就返回一条结果, 顺序与请求相同.
+	// 单条记录解析失败时不中断流, 错误写在结果的error字段中.
+	Resolve(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RawAddress, ResolveResult], error)
+}
+
+type addLibClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAddLibClient(cc grpc.ClientConnInterface) AddLibClient {
+	return &addLibClient{cc}
+}
+
+func (c *addLibClient) GetCode(ctx context.Context, in *AddressQuery, opts ...grpc.CallOption) (*CodeReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CodeReply)
+	err := c.cc.Invoke(ctx, AddLib_GetCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *addLibClient) ParseAddress(ctx context.Context, in *AddressQuery, opts ...grpc.CallOption) (*Address, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Address)
+	err := c.cc.Invoke(ctx, AddLib_ParseAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *addLibClient) ParseCode(ctx context.Context, in *CodeRequest, opts ...grpc.CallOption) (*AddressCodes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressCodes)
+	err := c.cc.Invoke(ctx, AddLib_ParseCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *addLibClient) GetRegion(ctx context.Context, in *CodeRequest, opts ...grpc.CallOption) (*Region, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Region)
+	err := c.cc.Invoke(ctx, AddLib_GetRegion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *addLibClient) Resolve(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RawAddress, ResolveResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AddLib_ServiceDesc.Streams[0], AddLib_Resolve_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RawAddress, ResolveResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AddLib_ResolveClient = grpc.BidiStreamingClient[RawAddress, ResolveResult]
+
+// AddLibServer is the server API for AddLib service.
+// All implementations must embed UnimplementedAddLibServer
+// for forward compatibility.
+type AddLibServer interface {
+	// 通过地址名称查询编码, 规则与LookupCode相同
+	GetCode(context.Context, *AddressQuery) (*CodeReply, error)
+	// 解析省市区名称, 规则与ParseAddress相同
+	ParseAddress(context.Context, *AddressQuery) (*Address, error)
+	// 输入地址编码, 输出其所属省市区编码
+	ParseCode(context.Context, *CodeRequest) (*AddressCodes, error)
+	// 通过编码查询地址区划
+	GetRegion(context.Context, *CodeRequest) (*Region, error)
+	// 批量解析原始地址. 每收到一条记录就返回一条结果, 顺序与请求相同.
+	// 单条记录解析失败时不中断流, 错误写在结果的error字段中.
+	Resolve(grpc.BidiStreamingServer[RawAddress, ResolveResult]) error
+	mustEmbedUnimplementedAddLibServer()
+}
+
+// UnimplementedAddLibServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAddLibServer struct{}
+
+func (UnimplementedAddLibServer) GetCode(context.Context, *AddressQuery) (*CodeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCode not implemented")
+}
+func (UnimplementedAddLibServer) ParseAddress(context.Context, *AddressQuery) (*Address, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseAddress not implemented")
+}
+func (UnimplementedAddLibServer) ParseCode(context.Context, *CodeRequest) (*AddressCodes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseCode not implemented")
+}
+func (UnimplementedAddLibServer) GetRegion(context.Context, *CodeRequest) (*Region, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegion not implemented")
+}
+func (UnimplementedAddLibServer) Resolve(grpc.BidiStreamingServer[RawAddress, ResolveResult]) error {
+	return status.Errorf(codes.Unimplemented, "method Resolve not implemented")
+}
+func (UnimplementedAddLibServer) mustEmbedUnimplementedAddLibServer() {}
+func (UnimplementedAddLibServer) testEmbeddedByValue()                {}
+
+// UnsafeAddLibServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AddLibServer will
+// result in compilation errors.
+type UnsafeAddLibServer interface {
+	mustEmbedUnimplementedAddLibServer()
+}
+
+func RegisterAddLibServer(s grpc.ServiceRegistrar, srv AddLibServer) {
+	// If the following call pancis, it indicates UnimplementedAddLibServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AddLib_ServiceDesc, srv)
+}
+
+func _AddLib_GetCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddLibServer).GetCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AddLib_GetCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddLibServer).GetCode(ctx, req.(*AddressQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AddLib_ParseAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddLibServer).ParseAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AddLib_ParseAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddLibServer).ParseAddress(ctx, req.(*AddressQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AddLib_ParseCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddLibServer).ParseCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AddLib_ParseCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddLibServer).ParseCode(ctx, req.(*CodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AddLib_GetRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddLibServer).GetRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AddLib_GetRegion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddLibServer).GetRegion(ctx, req.(*CodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AddLib_Resolve_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AddLibServer).Resolve(&grpc.GenericServerStream[RawAddress, ResolveResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AddLib_ResolveServer = grpc.BidiStreamingServer[RawAddress, ResolveResult]
+
+// AddLib_ServiceDesc is the grpc.ServiceDesc for AddLib service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AddLib_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "addlib.v1.AddLib",
+	HandlerType: (*AddLibServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCode",
+			Handler:    _AddLib_GetCode_Handler,
+		},
+		{
+			MethodName: "ParseAddress",
+			Handler:    _AddLib_ParseAddress_Handler,
+		},
+		{
+			MethodName: "ParseCode",
+			Handler:    _AddLib_ParseCode_Handler,
+		},
+		{
+			MethodName: "GetRegion",
+			Handler:    _AddLib_GetRegion_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Resolve",
+			Handler:       _AddLib_Resolve_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "addlib.proto",
+}
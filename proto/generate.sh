@@ -0,0 +1,11 @@
+#!/bin/sh
+# 生成proto/addlibpb中的Go代码(需要protoc, protoc-gen-go和protoc-gen-go-grpc), 并加上grpc构建标签.
+# 生成的代码依赖google.golang.org/protobuf和google.golang.org/grpc. 加上标签后, 没有安装这些依赖时go build ./...会跳过它们.
+# 修改addlib.proto之后运行, 并提交生成的代码.
+set -e
+cd "$(dirname "$0")"
+${PROTOC:-protoc} -I . --go_out=.. --go_opt=module=goAddLib --go-grpc_out=.. --go-grpc_opt=module=goAddLib addlib.proto
+for f in addlibpb/*.pb.go; do
+	{ printf '//go:build grpc\n\n'; cat "$f"; } > "$f.tmp"
+	mv "$f.tmp" "$f"
+done
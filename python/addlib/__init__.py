@@ -0,0 +1,260 @@
+"""中国行政区划地址库(goAddLib的Python封装).
+
+例:
+    >>> import addlib
+    >>> addlib.get_code("浙江", "杭州", "西湖")
+    'CN033001012'
+    >>> addlib.parse_text("浙江省杭州市西湖区文三路100号")
+    (Address(province='浙江省', city='杭州市', district='西湖区', municipality=False, code='CN033001012'), '文三路100号')
+
+导入时加载包中的数据文件(或环境变量ADDLIB_DATA指定的数据文件夹), 可以用init()重新加载.
+查询失败时抛出AddLibError的子类: NotFoundError, AmbiguousError, InconsistentError或BadInputError.
+
+get_codes, parse_addresses等以复数命名的函数一次处理一整列数据(列表, 元组或pandas.Series), 只跨越一次cgo调用:
+    >>> df["code"] = addlib.get_codes(df["province"], df["city"], df["district"], errors="coerce")
+"""
+
+import os
+import sys
+from dataclasses import dataclass
+from typing import List, Optional, Tuple
+
+from ._native import (
+    AddLibError,
+    AmbiguousError,
+    BadInputError,
+    DataError,
+    InconsistentError,
+    NotFoundError,
+    call,
+    call_lines,
+    error_for,
+)
+
+__all__ = [
+    "Address", "AddressCodes", "Region",
+    "AddLibError", "BadInputError", "NotFoundError", "AmbiguousError", "InconsistentError", "DataError",
+    "init", "provinces", "cities", "districts", "province_codes", "city_codes", "district_codes",
+    "get_name", "get_code", "get_province_code", "get_city_code", "get_district_code",
+    "parse_address", "parse_code", "parse_text", "get_region", "region_path",
+    "get_codes", "get_names", "parse_addresses", "parse_codes", "parse_texts",
+]
+
+
+@dataclass(frozen=True)
+class Address:
+    """标准三级地址, 对应Go的Address. code为最具体的一级的编码."""
+
+    province: str
+    city: str
+    district: str
+    municipality: bool = False
+    code: str = ""
+
+    def __str__(self):
+        # 直辖市的省名和市名相同, 只保留一个
+        if self.municipality and self.city == self.province:
+            return self.province + self.district
+        return self.province + self.city + self.district
+
+    @classmethod
+    def _from_json(cls, data):
+        return cls(data["province"], data["city"], data["district"], data["municipality"], data["code"])
+
+    @classmethod
+    def _from_fields(cls, fields):
+        return cls(fields[0], fields[1], fields[2], fields[3] == "1", fields[4])
+
+
+@dataclass(frozen=True)
+class AddressCodes:
+    """地址所属的省市区编码, 对应Go的AddressCodes"""
+
+    province_code: str
+    city_code: str
+    district_code: str
+
+
+@dataclass(frozen=True)
+class Region:
+    """地址区划, 对应Go的Region"""
+
+    code: str
+    name: str
+    short_name: str
+    level: str  # province, city或district
+    kind: str  # 行政区划类型, 例如: 市辖区
+    parent_code: str  # 省的父节点编码为""
+    gb_code: str  # 国家标准行政区划代码, 若数据中没有则为""
+
+
+def init(data_path):
+    """加载数据文件夹, 输出区划的个数. 失败时抛出DataError, 并保留原来的地址库."""
+    return call("initializeJSON", os.fspath(data_path))["regions"]
+
+
+def provinces(mainland=False) -> List[str]:
+    """所有省名. mainland为True时只输出大陆的省."""
+    return call("provincesJSON", mainland)
+
+
+def cities(province) -> List[str]:
+    """省所管辖的市名"""
+    return call("citiesJSON", province)
+
+
+def districts(city) -> List[str]:
+    """市所管辖的区名"""
+    return call("districtsJSON", city)
+
+
+def province_codes(mainland=False) -> List[str]:
+    """所有省编码"""
+    return call("provinceCodesJSON", mainland)
+
+
+def city_codes(province_code) -> List[str]:
+    """省编码所管辖的市编码"""
+    return call("cityCodesJSON", province_code)
+
+
+def district_codes(city_code) -> List[str]:
+    """市编码所管辖的区编码"""
+    return call("districtCodesJSON", city_code)
+
+
+def get_name(code) -> str:
+    """编码对应的标准名称"""
+    return call("getNameJSON", code)
+
+
+def get_code(province="", city="", district="") -> str:
+    """地址名称对应的编码, 规则与Go的LookupCode相同"""
+    return call("getCodeJSON", province, city, district)
+
+
+def get_province_code(province) -> str:
+    return call("getProvinceCodeJSON", province)
+
+
+def get_city_code(city) -> str:
+    return call("getCityCodeJSON", city)
+
+
+def get_district_code(city, district) -> str:
+    return call("getDistrictCodeJSON", city, district)
+
+
+def parse_address(province="", city="", district="") -> Address:
+    """解析省市区名称, 输出标准三级地址"""
+    return Address._from_json(call("parseAddressJSON", province, city, district))
+
+
+def parse_code(code) -> AddressCodes:
+    """输出编码所属的省市区编码"""
+    return AddressCodes(**call("parseCodeJSON", code))
+
+
+def parse_text(text) -> Tuple[Address, str]:
+    """解析自由文本中的地址, 输出标准三级地址和剩余的详细地址"""
+    result = call("parseTextJSON", text)
+    return Address._from_json(result), result.get("detail", "")
+
+
+def get_region(code) -> Region:
+    return Region(**call("getRegionJSON", code))
+
+
+def region_path(code) -> List[Region]:
+    """从省到code的区划"""
+    return [Region(**region) for region in call("regionPathJSON", code)]
+
+
+# 以下为批量函数. 每个参数是一列数据, 输出与输入的行一一对应的列表.
+# None和缺失值(NaN, pandas.NA等)视为"", 字符串中的"\t", "\r", "\n"替换为空格, "\x00"删除. errors为"raise"时遇到第一个失败的行就抛出异常, 为"coerce"时失败的行输出None.
+
+
+# 分隔符替换为空格. "\x00"删除, 否则C字符串在此截断, 输出的行数会少于输入.
+_SEPARATORS = str.maketrans("\t\r\n", "   ", "\x00")
+
+
+def _missing(value):
+    """判断是否为缺失值: NaN, pandas.NA或NaT等"""
+    # 值是pandas.NA时pandas一定已经导入, 因此不需要在这里导入pandas
+    pd = sys.modules.get("pandas")
+    if pd is not None and pd.api.types.is_scalar(value):
+        return bool(pd.isna(value))
+    try:
+        return bool(value != value)  # NaN
+    except TypeError:
+        return False
+
+
+def _cell(value):
+    if value.__class__ is not str:
+        if value is None or _missing(value):
+            return ""
+        value = str(value)
+    if "\t" in value or "\n" in value or "\r" in value or "\x00" in value:
+        return value.translate(_SEPARATORS)
+    return value
+
+
+def _lines(*columns):
+    """把多列数据按行拼接为以"\\t"分隔的字符串. 值为None的列视为全部为""."""
+    columns = [list(column) if column is not None else None for column in columns]
+    sizes = {len(column) for column in columns if column is not None}
+    if len(sizes) > 1:
+        raise BadInputError("columns must have the same length")
+    size = sizes.pop() if sizes else 0
+    columns = [[_cell(value) for value in column] if column is not None else [""] * size for column in columns]
+    return ["\t".join(row) for row in zip(*columns)]
+
+
+def _batch(name, lines, errors, convert):
+    if errors not in ("raise", "coerce"):
+        raise ValueError("errors must be 'raise' or 'coerce'")
+    rows = call_lines(name, lines)
+    if len(rows) != len(lines):
+        raise AddLibError("%s: expected %d rows, got: %d" % (name, len(lines), len(rows)))
+    results = []
+    for i, (fields, code) in enumerate(rows):
+        if code:
+            if errors == "raise":
+                raise error_for(code, "%s, row = %d, input = %s" % (code, i, lines[i]))
+            results.append(None)
+        else:
+            results.append(convert(fields))
+    return results
+
+
+def get_codes(provinces, cities, districts, errors="raise") -> List[Optional[str]]:
+    """批量查询编码. 省市区的列可以为None, 例如get_codes(None, cities, districts)."""
+    return _batch("getCodes", _lines(provinces, cities, districts), errors, lambda fields: fields[0])
+
+
+def get_names(codes, errors="raise") -> List[Optional[str]]:
+    """批量查询名称"""
+    return _batch("getNames", _lines(codes), errors, lambda fields: fields[0])
+
+
+def parse_addresses(provinces, cities, districts, errors="raise") -> List[Optional[Address]]:
+    """批量解析省市区名称"""
+    return _batch("parseAddresses", _lines(provinces, cities, districts), errors, Address._from_fields)
+
+
+def parse_codes(codes, errors="raise") -> List[Optional[AddressCodes]]:
+    """批量解析编码"""
+    return _batch("parseCodes", _lines(codes), errors, lambda fields: AddressCodes(*fields))
+
+
+def parse_texts(texts, errors="raise") -> List[Optional[Tuple[Address, str]]]:
+    """批量解析自由文本, 每行输出(标准三级地址, 详细地址)"""
+    return _batch("parseTexts", _lines(texts), errors, lambda fields: (Address._from_fields(fields), fields[5]))
+
+
+def _default_data_path():
+    return os.environ.get("ADDLIB_DATA") or os.path.join(os.path.dirname(os.path.abspath(__file__)), "lib.add")
+
+
+init(_default_data_path())
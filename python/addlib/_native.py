@@ -0,0 +1,143 @@
+"""addlib.so的ctypes绑定.
+
+调用Go导出的JSON接口(参考export.go), 返回的字符串用freeString释放.
+"""
+
+import ctypes
+import json
+import os
+
+_HERE = os.path.dirname(os.path.abspath(__file__))
+
+
+class AddLibError(Exception):
+    """地址库错误的基类. code为export.go中的错误码."""
+
+    code = "internal"
+
+    def __init__(self, message):
+        super().__init__(message)
+        self.message = message
+
+
+class BadInputError(AddLibError, ValueError):
+    """输入为空, 不是有效的UTF-8或JSON"""
+
+    code = "bad_input"
+
+
+class NotFoundError(AddLibError, LookupError):
+    """地址名称或编码不存在"""
+
+    code = "not_found"
+
+
+class AmbiguousError(AddLibError, LookupError):
+    """地址名称对应多个编码, 需要输入更多的汉字"""
+
+    code = "ambiguous"
+
+
+class InconsistentError(AddLibError, LookupError):
+    """省市区之间不存在管辖关系"""
+
+    code = "inconsistent"
+
+
+class DataError(AddLibError):
+    """数据文件错误"""
+
+    code = "data_error"
+
+
+_ERRORS = {cls.code: cls for cls in (BadInputError, NotFoundError, AmbiguousError, InconsistentError, DataError)}
+
+# JSON接口的函数 -> 参数类型
+_FUNCTIONS = {
+    "initializeJSON": [ctypes.c_char_p],
+    "provincesJSON": [ctypes.c_bool],
+    "provinceCodesJSON": [ctypes.c_bool],
+    "citiesJSON": [ctypes.c_char_p],
+    "districtsJSON": [ctypes.c_char_p],
+    "cityCodesJSON": [ctypes.c_char_p],
+    "districtCodesJSON": [ctypes.c_char_p],
+    "getNameJSON": [ctypes.c_char_p],
+    "getCodeJSON": [ctypes.c_char_p] * 3,
+    "getProvinceCodeJSON": [ctypes.c_char_p],
+    "getCityCodeJSON": [ctypes.c_char_p],
+    "getDistrictCodeJSON": [ctypes.c_char_p] * 2,
+    "parseAddressJSON": [ctypes.c_char_p] * 3,
+    "parseCodeJSON": [ctypes.c_char_p],
+    "parseTextJSON": [ctypes.c_char_p],
+    "getRegionJSON": [ctypes.c_char_p],
+    "regionPathJSON": [ctypes.c_char_p],
+    "batchJSON": [ctypes.c_char_p] * 2,
+    # 按行批量调用
+    "getCodes": [ctypes.c_char_p],
+    "getNames": [ctypes.c_char_p],
+    "parseAddresses": [ctypes.c_char_p],
+    "parseCodes": [ctypes.c_char_p],
+    "parseTexts": [ctypes.c_char_p],
+}
+
+
+def _load():
+    """加载动态链接库. 默认为包中的_addlib.so, 也可以用环境变量ADDLIB_LIBRARY指定."""
+    path = os.environ.get("ADDLIB_LIBRARY") or os.path.join(_HERE, "_addlib.so")
+    try:
+        lib = ctypes.CDLL(path)
+    except OSError as e:
+        raise ImportError("cannot load %s, run python/build.sh first: %s" % (path, e)) from e
+    lib.freeString.argtypes = [ctypes.c_void_p]
+    lib.freeString.restype = None
+    for name, argtypes in _FUNCTIONS.items():
+        fn = getattr(lib, name)
+        fn.argtypes = argtypes
+        # 不能用c_char_p, 否则无法取得原来的指针并释放
+        fn.restype = ctypes.c_void_p
+    return lib
+
+
+_lib = _load()
+
+
+def _encode(arg):
+    if isinstance(arg, str):
+        return arg.encode("utf-8")
+    return arg
+
+
+def _call_raw(name, *args):
+    ptr = getattr(_lib, name)(*[_encode(arg) for arg in args])
+    try:
+        return ctypes.string_at(ptr).decode("utf-8")
+    finally:
+        _lib.freeString(ptr)
+
+
+def call(name, *args):
+    """调用JSON接口, 输出result. 若失败, 则抛出对应的AddLibError."""
+    return unwrap(json.loads(_call_raw(name, *args)))
+
+
+def unwrap(reply):
+    """输出{"result", "error"}中的result. 若error不为null, 则抛出对应的AddLibError."""
+    error = reply.get("error")
+    if error:
+        raise _ERRORS.get(error["code"], AddLibError)(error["message"])
+    return reply["result"]
+
+
+def call_lines(name, lines):
+    """按行批量调用, 输出每行的(字段列表, 错误码). lines中的每一行是以"\\t"分隔的字段, 不能包含"\\n"."""
+    output = _call_raw(name, "\n".join(lines) + "\n") if lines else ""
+    rows = []
+    for line in output.split("\n")[:-1]:
+        fields, _, code = line.rpartition("\t")
+        rows.append((fields.split("\t"), code))
+    return rows
+
+
+def error_for(code, message):
+    """错误码对应的AddLibError"""
+    return _ERRORS.get(code, AddLibError)(message)
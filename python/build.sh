@@ -0,0 +1,11 @@
+#!/bin/sh
+# 生成Python包中的动态链接库(addlib/_addlib.so)和数据文件(addlib/lib.add).
+# 需要在GOPATH/src/goAddLib/python下运行(参考export.go), 然后可以用pip install .安装.
+set -e
+cd "$(dirname "$0")"
+GO=${GO:-go}
+(cd .. && $GO build -buildmode=c-shared -o python/addlib/_addlib.so ./export.go)
+rm -f addlib/_addlib.h
+rm -rf addlib/lib.add
+cp -R ../lib.add addlib/lib.add
+echo "built addlib/_addlib.so"
@@ -0,0 +1,5 @@
+import os
+import sys
+
+# 不安装也可以直接在python目录下运行pytest
+sys.path.insert(0, os.path.join(os.path.dirname(os.path.abspath(__file__)), ".."))
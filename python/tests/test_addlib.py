@@ -0,0 +1,139 @@
+import os
+
+import pytest
+
+import addlib
+from addlib import Address, AddressCodes
+
+DATA_PATH = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..", "..", "lib.add")
+
+
+@pytest.mark.parametrize("args, expected", [
+    (("浙江", "杭州", "西湖"), "CN033001012"),
+    (("", "杭州", "西湖"), "CN033001012"),
+    (("", "杭州", ""), "CN033001000"),
+    (("浙江", "", ""), "CN033000000"),
+])
+def test_get_code(args, expected):
+    assert addlib.get_code(*args) == expected
+
+
+@pytest.mark.parametrize("args, error", [
+    (("", "foo", ""), addlib.NotFoundError),
+    (("江苏", "杭州", ""), addlib.InconsistentError),
+    (("", "", ""), addlib.BadInputError),
+])
+def test_get_code_errors(args, error):
+    with pytest.raises(error):
+        addlib.get_code(*args)
+
+
+def test_errors_are_builtin_exceptions():
+    with pytest.raises(LookupError):
+        addlib.get_name("foo")
+    with pytest.raises(ValueError):
+        addlib.get_name("")
+
+
+def test_get_name():
+    assert addlib.get_name("CN033001012") == "西湖区"
+    assert addlib.get_province_code("浙江") == "CN033000000"
+    assert addlib.get_city_code("杭州") == "CN033001000"
+    assert addlib.get_district_code("杭州", "西湖") == "CN033001012"
+
+
+def test_parse_address():
+    add = addlib.parse_address("", "杭州", "西湖")
+    assert add == Address("浙江省", "杭州市", "西湖区", False, "CN033001012")
+    assert str(add) == "浙江省杭州市西湖区"
+    assert str(addlib.parse_address("北京", "", "东城")) == "北京市东城区"
+
+
+def test_parse_code():
+    assert addlib.parse_code("CN033001012") == AddressCodes("CN033000000", "CN033001000", "CN033001012")
+    with pytest.raises(addlib.NotFoundError):
+        addlib.parse_code("foo")
+
+
+def test_parse_text():
+    add, detail = addlib.parse_text("浙江省杭州市西湖区文三路100号")
+    assert add == Address("浙江省", "杭州市", "西湖区", False, "CN033001012")
+    assert detail == "文三路100号"
+
+
+def test_region():
+    region = addlib.get_region("CN033001012")
+    assert (region.name, region.short_name, region.level, region.parent_code) == ("西湖区", "西湖", "district", "CN033001000")
+    assert [r.name for r in addlib.region_path("CN033001012")] == ["浙江省", "杭州市", "西湖区"]
+
+
+def test_lists():
+    assert "浙江省" in addlib.provinces(True)
+    assert len(addlib.provinces(True)) < len(addlib.provinces())
+    assert "杭州市" in addlib.cities("浙江")
+    assert "西湖区" in addlib.districts("杭州")
+    assert "CN033001000" in addlib.city_codes("CN033000000")
+    assert "CN033001012" in addlib.district_codes("CN033001000")
+    with pytest.raises(addlib.BadInputError):
+        addlib.district_codes("CN033000000")
+
+
+def test_init():
+    assert addlib.init(DATA_PATH) > 3000
+    with pytest.raises(addlib.DataError):
+        addlib.init("/nonexistent")
+    # 加载失败时保留原来的地址库
+    assert addlib.get_name("CN033001012") == "西湖区"
+
+
+def test_get_codes():
+    provinces = ["浙江", "", None, ""]
+    cities = ["杭州", "杭州", "foo", float("nan")]
+    districts = ["西湖", "", "", None]
+    assert addlib.get_codes(provinces, cities, districts, errors="coerce") == ["CN033001012", "CN033001000", None, None]
+    assert addlib.get_codes(None, ["杭州"], ["西湖"]) == ["CN033001012"]
+    assert addlib.get_codes([], [], []) == []
+    with pytest.raises(addlib.NotFoundError, match="row = 2"):
+        addlib.get_codes(provinces, cities, districts)
+    with pytest.raises(addlib.BadInputError):
+        addlib.get_codes(["浙江"], ["杭州", "宁波"], None)
+
+
+def test_batch_functions():
+    assert addlib.get_names(("CN033001012", "foo"), errors="coerce") == ["西湖区", None]
+    assert addlib.parse_addresses(["北京"], [""], ["东城"]) == [Address("北京市", "北京市", "东城区", True, "CN003001004")]
+    assert addlib.parse_codes(["CN033001012"]) == [AddressCodes("CN033000000", "CN033001000", "CN033001012")]
+    assert addlib.parse_texts(["浙江省杭州市西湖区文三路100号", "\n"], errors="coerce") == [
+        (Address("浙江省", "杭州市", "西湖区", False, "CN033001012"), "文三路100号"), None]
+
+
+def test_batch_cells():
+    assert addlib.get_codes(None, ["杭\x00州", "杭州\x00"], ["西湖", None]) == ["CN033001012", "CN033001000"]
+    assert addlib.get_names(["CN033001012\x00foo"], errors="coerce") == [None]
+
+
+def test_batch_pandas_na():
+    pd = pytest.importorskip("pandas")
+    cities = pd.Series(["杭州", pd.NA, None, float("nan")], dtype="object")
+    assert addlib.get_codes(None, cities, None, errors="coerce") == ["CN033001000", None, None, None]
+
+
+def test_batch_row_count():
+    call_lines = addlib.call_lines
+    addlib.call_lines = lambda name, lines: call_lines(name, lines)[:-1]
+    try:
+        with pytest.raises(addlib.AddLibError, match="expected 2 rows, got: 1"):
+            addlib.get_names(["CN033001012", "CN033001000"])
+    finally:
+        addlib.call_lines = call_lines
+
+
+def test_batch_matches_single_calls():
+    cities = [name for code in addlib.province_codes() for name in addlib.cities(addlib.get_name(code))]
+    codes = addlib.get_codes(None, cities, None, errors="coerce")
+    assert len(codes) == len(cities)
+    for city, code in zip(cities, codes):
+        try:
+            assert code == addlib.get_code("", city, "")
+        except addlib.AddLibError:
+            assert code is None